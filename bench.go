@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// bench solves every instance below the given directories and writes the
+// result tables. It returns the process exit status.
+func bench(args []string) int {
+	flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+	csvPath := flags.String("csv", "", "archivo CSV con una fila por instancia")
+	mdPath := flags.String("md", "", "archivo Markdown con las tablas de resultados y por familia")
+	options := solverFlags(flags)
+	workers := flags.Int("trabajadores", 0, "instancias que se resuelven a la vez (0 usa un trabajador por CPU)")
+	timeout := flags.Duration("limite", 0, "tiempo maximo por instancia, por ejemplo 10s (0 sin limite)")
+	optimaPath := flags.String("optimos", "", "archivo con lineas \"<instancia> <valor-optimo>\" que se agregan a los optimos conocidos")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para ejecutar ./main bench [opciones] <carpeta>...")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 2
+	}
+
+	optima, err := prpp.KnownOptima(*optimaPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	paths, err := prpp.FindInstances(flags.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// Ctrl-C stops the batch but the tables are still written with the
+	// instances solved so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	results := prpp.Bench(ctx, paths, optima, prpp.BenchOptions{Solver: options(), Workers: *workers, Timeout: *timeout})
+	if ctx.Err() != nil {
+		fmt.Fprintln(os.Stderr, "interrumpido: se escriben los resultados parciales")
+	}
+	stop()
+
+	if *csvPath != "" {
+		if err := writeFile(*csvPath, func(f *os.File) error { return prpp.WriteBenchCSV(f, results) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if *mdPath != "" {
+		if err := writeFile(*mdPath, func(f *os.File) error { return prpp.WriteBenchMarkdown(f, results) }); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	if *csvPath == "" && *mdPath == "" {
+		prpp.WriteBenchMarkdown(os.Stdout, results)
+	}
+
+	for _, r := range results {
+		if r.Err != nil {
+			return 1
+		}
+	}
+	return 0
+}
+
+// writeFile creates path and fills it with write.
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,645 @@
+// Package blossom computes minimum weight perfect matchings on general
+// graphs with Edmonds' blossom algorithm, in the O(n^3) primal-dual form
+// described by Galil ("Efficient algorithms for finding maximum matching in
+// graphs", 1986) and popularised by Joris van Rantwijk's mwmatching.
+//
+// Its interface mirrors the munkres package: fill a Matrix with the weights
+// between every pair of vertices and ask for the matching.
+package blossom
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Matrix holds the symmetric weights of a complete graph on n vertices.
+// The weight between i and j is A[i*n+j]; the diagonal is ignored.
+type Matrix struct {
+	n int
+	A []int64
+}
+
+func NewMatrix(n int) *Matrix {
+	m := new(Matrix)
+	m.n = n
+	m.A = make([]int64, n*n)
+	return m
+}
+
+// Pair is one matched pair of vertices, with Start() < End().
+type Pair struct {
+	a, b int
+}
+
+func (p Pair) Start() int {
+	return p.a
+}
+
+func (p Pair) End() int {
+	return p.b
+}
+
+// ComputeMinPerfect returns a perfect matching of minimum total weight.
+// The pairs are ordered by their first vertex.
+func ComputeMinPerfect(m *Matrix) ([]Pair, error) {
+	if m.n%2 != 0 {
+		return nil, fmt.Errorf("a perfect matching needs an even number of vertices, got %d", m.n)
+	}
+	if m.n == 0 {
+		return []Pair{}, nil
+	}
+	// Minimizing the weight of a perfect matching is maximizing the weight
+	// of a maximum cardinality matching under w' = max - w.
+	var maxWeight int64
+	for i := 0; i < m.n; i++ {
+		for j := i + 1; j < m.n; j++ {
+			if m.A[i*m.n+j] != m.A[j*m.n+i] {
+				return nil, fmt.Errorf("weight matrix is not symmetric at (%d, %d)", i, j)
+			}
+			if m.A[i*m.n+j] > maxWeight {
+				maxWeight = m.A[i*m.n+j]
+			}
+		}
+	}
+	edges := make([]wedge, 0, m.n*(m.n-1)/2)
+	for i := 0; i < m.n; i++ {
+		for j := i + 1; j < m.n; j++ {
+			edges = append(edges, wedge{i, j, maxWeight - m.A[i*m.n+j] + 1})
+		}
+	}
+
+	mate := newMatcher(m.n, edges).run()
+	pairs := make([]Pair, 0, m.n/2)
+	for v, w := range mate {
+		if w < 0 {
+			return nil, errors.New("no perfect matching found")
+		}
+		if v < w {
+			pairs = append(pairs, Pair{v, w})
+		}
+	}
+	return pairs, nil
+}
+
+type wedge struct {
+	i, j int
+	w    int64
+}
+
+// matcher is the state of the maximum weight matching algorithm. Vertices
+// are 0..n-1, non-trivial blossoms n..2n-1. An endpoint p of edge k is
+// 2k or 2k+1 and refers to vertex edges[k].i or edges[k].j respectively.
+type matcher struct {
+	n, nedge         int
+	edges            []wedge
+	endpoint         []int
+	neighbend        [][]int
+	mate             []int
+	label            []int
+	labelend         []int
+	inblossom        []int
+	blossomparent    []int
+	blossomchilds    [][]int
+	blossombase      []int
+	blossomendps     [][]int
+	bestedge         []int
+	blossombestedges [][]int
+	unusedblossoms   []int
+	dualvar          []int64
+	allowedge        []bool
+	queue            []int
+}
+
+func newMatcher(n int, edges []wedge) *matcher {
+	mt := &matcher{n: n, nedge: len(edges), edges: edges}
+	var maxWeight int64
+	for _, e := range edges {
+		if e.w > maxWeight {
+			maxWeight = e.w
+		}
+	}
+	mt.endpoint = make([]int, 2*len(edges))
+	mt.neighbend = make([][]int, n)
+	for k, e := range edges {
+		mt.endpoint[2*k] = e.i
+		mt.endpoint[2*k+1] = e.j
+		mt.neighbend[e.i] = append(mt.neighbend[e.i], 2*k+1)
+		mt.neighbend[e.j] = append(mt.neighbend[e.j], 2*k)
+	}
+	mt.mate = filled(n, -1)
+	mt.label = make([]int, 2*n)
+	mt.labelend = filled(2*n, -1)
+	mt.inblossom = make([]int, n)
+	mt.blossomparent = filled(2*n, -1)
+	mt.blossomchilds = make([][]int, 2*n)
+	mt.blossombase = filled(2*n, -1)
+	mt.blossomendps = make([][]int, 2*n)
+	mt.bestedge = filled(2*n, -1)
+	mt.blossombestedges = make([][]int, 2*n)
+	mt.dualvar = make([]int64, 2*n)
+	mt.allowedge = make([]bool, len(edges))
+	for v := 0; v < n; v++ {
+		mt.inblossom[v] = v
+		mt.blossombase[v] = v
+		mt.dualvar[v] = maxWeight
+		mt.unusedblossoms = append(mt.unusedblossoms, n+v)
+	}
+	return mt
+}
+
+func filled(size, value int) []int {
+	s := make([]int, size)
+	for i := range s {
+		s[i] = value
+	}
+	return s
+}
+
+func (mt *matcher) slack(k int) int64 {
+	e := mt.edges[k]
+	return mt.dualvar[e.i] + mt.dualvar[e.j] - 2*e.w
+}
+
+// leaves appends the vertices contained in blossom b to out.
+func (mt *matcher) leaves(b int, out []int) []int {
+	if b < mt.n {
+		return append(out, b)
+	}
+	for _, t := range mt.blossomchilds[b] {
+		out = mt.leaves(t, out)
+	}
+	return out
+}
+
+// assignLabel labels the top-level blossom containing w with t (1 for S,
+// 2 for T) reached through endpoint p, and labels its mate S if t is T.
+func (mt *matcher) assignLabel(w, t, p int) {
+	b := mt.inblossom[w]
+	mt.label[w], mt.label[b] = t, t
+	mt.labelend[w], mt.labelend[b] = p, p
+	mt.bestedge[w], mt.bestedge[b] = -1, -1
+	if t == 1 {
+		mt.queue = mt.leaves(b, mt.queue)
+	} else if t == 2 {
+		base := mt.blossombase[b]
+		mt.assignLabel(mt.endpoint[mt.mate[base]], 1, mt.mate[base]^1)
+	}
+}
+
+// scanBlossom traces back from v and w to find a new blossom or an
+// augmenting path. It returns the base of the blossom or -1.
+func (mt *matcher) scanBlossom(v, w int) int {
+	path := []int{}
+	base := -1
+	for v != -1 || w != -1 {
+		b := mt.inblossom[v]
+		if mt.label[b]&4 != 0 {
+			base = mt.blossombase[b]
+			break
+		}
+		path = append(path, b)
+		mt.label[b] = 5
+		if mt.labelend[b] == -1 {
+			v = -1
+		} else {
+			v = mt.endpoint[mt.labelend[b]]
+			b = mt.inblossom[v]
+			v = mt.endpoint[mt.labelend[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		mt.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom builds a blossom with the given base through S-S edge k.
+func (mt *matcher) addBlossom(base, k int) {
+	v, w := mt.edges[k].i, mt.edges[k].j
+	bb := mt.inblossom[base]
+	bv := mt.inblossom[v]
+	bw := mt.inblossom[w]
+	b := mt.unusedblossoms[len(mt.unusedblossoms)-1]
+	mt.unusedblossoms = mt.unusedblossoms[:len(mt.unusedblossoms)-1]
+	mt.blossombase[b] = base
+	mt.blossomparent[b] = -1
+	mt.blossomparent[bb] = b
+	path := []int{}
+	endps := []int{}
+	for bv != bb {
+		mt.blossomparent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, mt.labelend[bv])
+		v = mt.endpoint[mt.labelend[bv]]
+		bv = mt.inblossom[v]
+	}
+	path = append(path, bb)
+	reverse(path)
+	reverse(endps)
+	endps = append(endps, 2*k)
+	for bw != bb {
+		mt.blossomparent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, mt.labelend[bw]^1)
+		w = mt.endpoint[mt.labelend[bw]]
+		bw = mt.inblossom[w]
+	}
+	mt.blossomchilds[b] = path
+	mt.blossomendps[b] = endps
+	mt.label[b] = 1
+	mt.labelend[b] = mt.labelend[bb]
+	mt.dualvar[b] = 0
+	for _, v := range mt.leaves(b, nil) {
+		if mt.label[mt.inblossom[v]] == 2 {
+			mt.queue = append(mt.queue, v)
+		}
+		mt.inblossom[v] = b
+	}
+
+	bestedgeto := filled(2*mt.n, -1)
+	for _, bv := range path {
+		var nblists [][]int
+		if mt.blossombestedges[bv] == nil {
+			for _, v := range mt.leaves(bv, nil) {
+				nblist := make([]int, 0, len(mt.neighbend[v]))
+				for _, p := range mt.neighbend[v] {
+					nblist = append(nblist, p/2)
+				}
+				nblists = append(nblists, nblist)
+			}
+		} else {
+			nblists = [][]int{mt.blossombestedges[bv]}
+		}
+		for _, nblist := range nblists {
+			for _, k := range nblist {
+				i, j := mt.edges[k].i, mt.edges[k].j
+				if mt.inblossom[j] == b {
+					i, j = j, i
+				}
+				bj := mt.inblossom[j]
+				if bj != b && mt.label[bj] == 1 &&
+					(bestedgeto[bj] == -1 || mt.slack(k) < mt.slack(bestedgeto[bj])) {
+					bestedgeto[bj] = k
+				}
+			}
+		}
+		mt.blossombestedges[bv] = nil
+		mt.bestedge[bv] = -1
+	}
+	best := []int{}
+	for _, k := range bestedgeto {
+		if k != -1 {
+			best = append(best, k)
+		}
+	}
+	mt.blossombestedges[b] = best
+	mt.bestedge[b] = -1
+	for _, k := range best {
+		if mt.bestedge[b] == -1 || mt.slack(k) < mt.slack(mt.bestedge[b]) {
+			mt.bestedge[b] = k
+		}
+	}
+}
+
+// expandBlossom dissolves blossom b into its sub-blossoms.
+func (mt *matcher) expandBlossom(b int, endstage bool) {
+	for _, s := range mt.blossomchilds[b] {
+		mt.blossomparent[s] = -1
+		if s < mt.n {
+			mt.inblossom[s] = s
+		} else if endstage && mt.dualvar[s] == 0 {
+			mt.expandBlossom(s, endstage)
+		} else {
+			for _, v := range mt.leaves(s, nil) {
+				mt.inblossom[v] = s
+			}
+		}
+	}
+	if !endstage && mt.label[b] == 2 {
+		childs := mt.blossomchilds[b]
+		endps := mt.blossomendps[b]
+		entrychild := mt.inblossom[mt.endpoint[mt.labelend[b]^1]]
+		j := indexOf(childs, entrychild)
+		var jstep, endptrick int
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep = 1
+			endptrick = 0
+		} else {
+			jstep = -1
+			endptrick = 1
+		}
+		p := mt.labelend[b]
+		for j != 0 {
+			mt.label[mt.endpoint[p^1]] = 0
+			mt.label[mt.endpoint[at(endps, j-endptrick)^endptrick^1]] = 0
+			mt.assignLabel(mt.endpoint[p^1], 2, p)
+			mt.allowedge[at(endps, j-endptrick)/2] = true
+			j += jstep
+			p = at(endps, j-endptrick) ^ endptrick
+			mt.allowedge[p/2] = true
+			j += jstep
+		}
+		bv := at(childs, j)
+		mt.label[mt.endpoint[p^1]], mt.label[bv] = 2, 2
+		mt.labelend[mt.endpoint[p^1]], mt.labelend[bv] = p, p
+		mt.bestedge[bv] = -1
+		j += jstep
+		for at(childs, j) != entrychild {
+			bv := at(childs, j)
+			if mt.label[bv] == 1 {
+				j += jstep
+				continue
+			}
+			labeled := -1
+			for _, v := range mt.leaves(bv, nil) {
+				if mt.label[v] != 0 {
+					labeled = v
+					break
+				}
+			}
+			if labeled >= 0 {
+				mt.label[labeled] = 0
+				mt.label[mt.endpoint[mt.mate[mt.blossombase[bv]]]] = 0
+				mt.assignLabel(labeled, 2, mt.labelend[labeled])
+			}
+			j += jstep
+		}
+	}
+	mt.label[b], mt.labelend[b] = -1, -1
+	mt.blossomchilds[b], mt.blossomendps[b] = nil, nil
+	mt.blossombase[b] = -1
+	mt.blossombestedges[b] = nil
+	mt.bestedge[b] = -1
+	mt.unusedblossoms = append(mt.unusedblossoms, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges inside blossom b along
+// the path from vertex v to the base, making v the new base.
+func (mt *matcher) augmentBlossom(b, v int) {
+	t := v
+	for mt.blossomparent[t] != b {
+		t = mt.blossomparent[t]
+	}
+	if t >= mt.n {
+		mt.augmentBlossom(t, v)
+	}
+	childs := mt.blossomchilds[b]
+	endps := mt.blossomendps[b]
+	i := indexOf(childs, t)
+	j := i
+	var jstep, endptrick int
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep = 1
+		endptrick = 0
+	} else {
+		jstep = -1
+		endptrick = 1
+	}
+	for j != 0 {
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-endptrick) ^ endptrick
+		if t >= mt.n {
+			mt.augmentBlossom(t, mt.endpoint[p])
+		}
+		j += jstep
+		t = at(childs, j)
+		if t >= mt.n {
+			mt.augmentBlossom(t, mt.endpoint[p^1])
+		}
+		mt.mate[mt.endpoint[p]] = p ^ 1
+		mt.mate[mt.endpoint[p^1]] = p
+	}
+	mt.blossomchilds[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+	mt.blossomendps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+	mt.blossombase[b] = mt.blossombase[mt.blossomchilds[b][0]]
+}
+
+// augmentMatching flips the augmenting path through edge k.
+func (mt *matcher) augmentMatching(k int) {
+	v, w := mt.edges[k].i, mt.edges[k].j
+	for _, sp := range [2][2]int{{v, 2*k + 1}, {w, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := mt.inblossom[s]
+			if bs >= mt.n {
+				mt.augmentBlossom(bs, s)
+			}
+			mt.mate[s] = p
+			if mt.labelend[bs] == -1 {
+				break
+			}
+			t := mt.endpoint[mt.labelend[bs]]
+			bt := mt.inblossom[t]
+			s = mt.endpoint[mt.labelend[bt]]
+			j := mt.endpoint[mt.labelend[bt]^1]
+			if bt >= mt.n {
+				mt.augmentBlossom(bt, j)
+			}
+			mt.mate[j] = mt.labelend[bt]
+			p = mt.labelend[bt] ^ 1
+		}
+	}
+}
+
+// run computes a maximum weight matching among the maximum cardinality
+// matchings and returns the mate of every vertex, or -1.
+func (mt *matcher) run() []int {
+	n := mt.n
+	for stage := 0; stage < n; stage++ {
+		for i := range mt.label {
+			mt.label[i] = 0
+			mt.bestedge[i] = -1
+		}
+		for b := n; b < 2*n; b++ {
+			mt.blossombestedges[b] = nil
+		}
+		for k := range mt.allowedge {
+			mt.allowedge[k] = false
+		}
+		mt.queue = mt.queue[:0]
+		for v := 0; v < n; v++ {
+			if mt.mate[v] == -1 && mt.label[mt.inblossom[v]] == 0 {
+				mt.assignLabel(v, 1, -1)
+			}
+		}
+
+		augmented := false
+		for {
+			for len(mt.queue) > 0 && !augmented {
+				v := mt.queue[len(mt.queue)-1]
+				mt.queue = mt.queue[:len(mt.queue)-1]
+				for _, p := range mt.neighbend[v] {
+					k := p / 2
+					w := mt.endpoint[p]
+					if mt.inblossom[v] == mt.inblossom[w] {
+						continue
+					}
+					var kslack int64
+					if !mt.allowedge[k] {
+						kslack = mt.slack(k)
+						if kslack <= 0 {
+							mt.allowedge[k] = true
+						}
+					}
+					if mt.allowedge[k] {
+						if mt.label[mt.inblossom[w]] == 0 {
+							mt.assignLabel(w, 2, p^1)
+						} else if mt.label[mt.inblossom[w]] == 1 {
+							base := mt.scanBlossom(v, w)
+							if base >= 0 {
+								mt.addBlossom(base, k)
+							} else {
+								mt.augmentMatching(k)
+								augmented = true
+								break
+							}
+						} else if mt.label[w] == 0 {
+							mt.label[w] = 2
+							mt.labelend[w] = p ^ 1
+						}
+					} else if mt.label[mt.inblossom[w]] == 1 {
+						b := mt.inblossom[v]
+						if mt.bestedge[b] == -1 || kslack < mt.slack(mt.bestedge[b]) {
+							mt.bestedge[b] = k
+						}
+					} else if mt.label[w] == 0 {
+						if mt.bestedge[w] == -1 || kslack < mt.slack(mt.bestedge[w]) {
+							mt.bestedge[w] = k
+						}
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+
+			// No augmenting path with the current duals: find the
+			// smallest dual change that makes progress.
+			deltatype := -1
+			var delta int64
+			deltaedge, deltablossom := -1, -1
+			for v := 0; v < n; v++ {
+				if mt.label[mt.inblossom[v]] == 0 && mt.bestedge[v] != -1 {
+					d := mt.slack(mt.bestedge[v])
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 2
+						deltaedge = mt.bestedge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				if mt.blossomparent[b] == -1 && mt.label[b] == 1 && mt.bestedge[b] != -1 {
+					d := mt.slack(mt.bestedge[b]) / 2
+					if deltatype == -1 || d < delta {
+						delta = d
+						deltatype = 3
+						deltaedge = mt.bestedge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if mt.blossombase[b] >= 0 && mt.blossomparent[b] == -1 && mt.label[b] == 2 &&
+					(deltatype == -1 || mt.dualvar[b] < delta) {
+					delta = mt.dualvar[b]
+					deltatype = 4
+					deltablossom = b
+				}
+			}
+			if deltatype == -1 {
+				// No further improvement possible; the matching is of
+				// maximum cardinality. Make the duals optimal and stop.
+				deltatype = 1
+				delta = mt.dualvar[0]
+				for v := 1; v < n; v++ {
+					if mt.dualvar[v] < delta {
+						delta = mt.dualvar[v]
+					}
+				}
+				if delta < 0 {
+					delta = 0
+				}
+			}
+
+			for v := 0; v < n; v++ {
+				if mt.label[mt.inblossom[v]] == 1 {
+					mt.dualvar[v] -= delta
+				} else if mt.label[mt.inblossom[v]] == 2 {
+					mt.dualvar[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if mt.blossombase[b] >= 0 && mt.blossomparent[b] == -1 {
+					if mt.label[b] == 1 {
+						mt.dualvar[b] += delta
+					} else if mt.label[b] == 2 {
+						mt.dualvar[b] -= delta
+					}
+				}
+			}
+
+			if deltatype == 1 {
+				break
+			} else if deltatype == 2 {
+				mt.allowedge[deltaedge] = true
+				i, j := mt.edges[deltaedge].i, mt.edges[deltaedge].j
+				if mt.label[mt.inblossom[i]] == 0 {
+					i, j = j, i
+				}
+				mt.queue = append(mt.queue, i)
+			} else if deltatype == 3 {
+				mt.allowedge[deltaedge] = true
+				mt.queue = append(mt.queue, mt.edges[deltaedge].i)
+			} else if deltatype == 4 {
+				mt.expandBlossom(deltablossom, false)
+			}
+		}
+		if !augmented {
+			break
+		}
+		for b := n; b < 2*n; b++ {
+			if mt.blossomparent[b] == -1 && mt.blossombase[b] >= 0 && mt.label[b] == 1 && mt.dualvar[b] == 0 {
+				mt.expandBlossom(b, true)
+			}
+		}
+	}
+
+	mate := make([]int, n)
+	for v := 0; v < n; v++ {
+		mate[v] = -1
+		if mt.mate[v] >= 0 {
+			mate[v] = mt.endpoint[mt.mate[v]]
+		}
+	}
+	return mate
+}
+
+func reverse(s []int) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+func indexOf(s []int, x int) int {
+	for i, y := range s {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// at indexes s allowing negative positions counted from the end, as the
+// blossom traversals walk around the cycle in either direction.
+func at(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
@@ -0,0 +1,94 @@
+package blossom
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// bruteForce returns the weight of a minimum perfect matching by trying
+// every pairing of the vertices.
+func bruteForce(m *Matrix, used []bool) int64 {
+	first := -1
+	for v := range used {
+		if !used[v] {
+			first = v
+			break
+		}
+	}
+	if first < 0 {
+		return 0
+	}
+	used[first] = true
+	best := int64(-1)
+	for v := first + 1; v < m.n; v++ {
+		if used[v] {
+			continue
+		}
+		used[v] = true
+		w := m.A[first*m.n+v] + bruteForce(m, used)
+		if best < 0 || w < best {
+			best = w
+		}
+		used[v] = false
+	}
+	used[first] = false
+	return best
+}
+
+func weight(t *testing.T, m *Matrix, pairs []Pair) int64 {
+	seen := make([]bool, m.n)
+	var total int64
+	for _, p := range pairs {
+		if p.Start() >= p.End() || seen[p.Start()] || seen[p.End()] {
+			t.Fatalf("invalid matching %v", pairs)
+		}
+		seen[p.Start()], seen[p.End()] = true, true
+		total += m.A[p.Start()*m.n+p.End()]
+	}
+	if len(pairs)*2 != m.n {
+		t.Fatalf("matching %v is not perfect on %d vertices", pairs, m.n)
+	}
+	return total
+}
+
+func Test_ComputeMinPerfect(t *testing.T) {
+	m := NewMatrix(4)
+	m.A = []int64{0, 1, 5, 5,
+		1, 0, 5, 5,
+		5, 5, 0, 1,
+		5, 5, 1, 0}
+	pairs, err := ComputeMinPerfect(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := weight(t, m, pairs); w != 2 {
+		t.Errorf("weight %d, expected 2 (%v)", w, pairs)
+	}
+}
+
+func Test_ComputeMinPerfectRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for iter := 0; iter < 500; iter++ {
+		n := 2 * (1 + rng.Intn(5))
+		m := NewMatrix(n)
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				w := int64(rng.Intn(20))
+				m.A[i*n+j], m.A[j*n+i] = w, w
+			}
+		}
+		pairs, err := ComputeMinPerfect(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := weight(t, m, pairs), bruteForce(m, make([]bool, n)); got != want {
+			t.Fatalf("iteration %d: weight %d, expected %d\n%v", iter, got, want, m.A)
+		}
+	}
+}
+
+func Test_ComputeMinPerfectOdd(t *testing.T) {
+	if _, err := ComputeMinPerfect(NewMatrix(3)); err == nil {
+		t.Error("expected an error for an odd number of vertices")
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// dot writes the GraphViz drawing of an instance, and of the solution found
+// for it unless only the instance is asked for, and returns the process
+// exit status.
+func dot(args []string) int {
+	flags := flag.NewFlagSet("dot", flag.ContinueOnError)
+	options := solverFlags(flags)
+	instanceOnly := flags.Bool("instancia", false, "dibujar solo la instancia, sin resolverla")
+	outPath := flags.String("o", "", "archivo de salida; sin el, el grafo se imprime en pantalla")
+	setDepot := depotFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para dibujar ./main dot [opciones] <nombre-archivo>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	inst, err := prpp.ReadInstance(flags.Arg(0))
+	if err == nil {
+		err = setDepot(inst)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	var solution *prpp.Solution
+	if !*instanceOnly {
+		solution, err = prpp.Solve(inst, options())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", flags.Arg(0), err)
+			return 1
+		}
+	}
+
+	write := func(f *os.File) error { return prpp.WriteDOT(f, inst, solution) }
+	if *outPath == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFile(*outPath, write)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
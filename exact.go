@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// exact solves an instance by branch and bound, writes its -salida.txt file
+// and returns the process exit status.
+func exact(args []string) int {
+	flags := flag.NewFlagSet("exact", flag.ContinueOnError)
+	nodes := flags.Int("nodos", 0, "maximo de nodos del arbol de busqueda (0 sin limite)")
+	timeLimit := flags.Duration("tiempo", 0, "tiempo maximo de busqueda, por ejemplo 1m (0 sin limite)")
+	progress := flags.Bool("progreso", false, "mostrar cada mejora de la solucion mientras se resuelve")
+	jsonOut := flags.Bool("json", false, "escribir tambien la solucion detallada en <nombre-archivo>-salida.json")
+	setDepot := depotFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para resolver exactamente ./main exact [opciones] <nombre-archivo>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	beginning := time.Now()
+	path := flags.Arg(0)
+	inst, err := prpp.ReadInstance(path)
+	if err == nil {
+		err = setDepot(inst)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// Ctrl-C stops the search and keeps the best solution found so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	var report func(prpp.Progress)
+	if *progress {
+		report = printProgress
+	}
+	solution, proven, err := prpp.SolveExactContext(ctx, inst, prpp.ExactLimits{Nodes: *nodes, TimeLimit: *timeLimit}, report)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	if err := writeFile(path+"-salida.txt", func(f *os.File) error { return prpp.WriteSolution(f, solution) }); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	// A finished search proves the walk optimal.
+	if *jsonOut {
+		if err := writeJSON(path, inst, solution, solution.Value, proven); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	fmt.Println()
+	fmt.Println(path)
+	fmt.Println("Tiempo de ejecucion: ", time.Since(beginning))
+	if proven {
+		fmt.Println("Valor Optimo: ", solution.Value)
+	} else {
+		fmt.Println("Mejor Valor (busqueda detenida): ", solution.Value)
+		fmt.Println("Cota Superior: ", solution.UpperBound)
+		fmt.Println("Brecha con la Cota: ", solution.Gap())
+	}
+	fmt.Println("Valor Heurística: ", solution.HeuristicValue)
+	fmt.Println("Nodos explorados: ", solution.Nodes)
+	return 0
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// generate writes random instances in the NoRPP format and returns the
+// process exit status.
+func generate(args []string) int {
+	flags := flag.NewFlagSet("gen", flag.ContinueOnError)
+	kind := flags.String("tipo", prpp.GenRandom, "tipo de grafo: random (densidad dada), grid (grilla) o degree (grado acotado)")
+	vertices := flags.Int("vertices", 20, "vertices de las instancias random y degree")
+	density := flags.Float64("densidad", 0.2, "fraccion de los pares de vertices unidos por un lado, entre 0 y 1 (random y degree)")
+	rows := flags.Int("filas", 4, "filas de la grilla")
+	columns := flags.Int("columnas", 4, "columnas de la grilla")
+	maxDegree := flags.Int("grado", 4, "grado maximo de los vertices (degree)")
+	required := flags.Float64("requeridos", 0.1, "fraccion de los lados que son requeridos, entre 0 y 1")
+	cost := flags.String("costo", "uniform:1:100", "distribucion de los costos: uniform:min:max, normal:media:desvio o const:valor")
+	benefit := flags.String("beneficio", "uniform:0:100", "distribucion de los beneficios de los lados no requeridos")
+	requiredBenefit := flags.String("beneficio-requerido", "uniform:50:300", "distribucion de los beneficios de los lados requeridos")
+	connected := flags.Bool("conexo", true, "garantizar que el grafo sea conexo")
+	seed := flags.Int64("semilla", 1, "semilla del generador aleatorio; la instancia i usa semilla+i")
+	count := flags.Int("cantidad", 1, "instancias a generar")
+	name := flags.String("nombre", "", "prefijo del nombre de las instancias (por defecto gen y la inicial del tipo)")
+	outPath := flags.String("o", "", "archivo de salida, o carpeta si se genera mas de una instancia; sin el, la instancia se imprime en pantalla")
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para generar ./main gen [opciones]")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 || *count < 1 || (*count > 1 && *outPath == "") {
+		flags.Usage()
+		return 2
+	}
+
+	opts := prpp.GenOptions{
+		Kind:      *kind,
+		Vertices:  *vertices,
+		Density:   *density,
+		Rows:      *rows,
+		Columns:   *columns,
+		MaxDegree: *maxDegree,
+		Required:  *required,
+		Connected: *connected,
+	}
+	for _, d := range []struct {
+		flag  string
+		value string
+		dist  *prpp.Distribution
+	}{{"costo", *cost, &opts.Cost}, {"beneficio", *benefit, &opts.Benefit}, {"beneficio-requerido", *requiredBenefit, &opts.RequiredBenefit}} {
+		dist, err := prpp.ParseDistribution(d.value)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "-%s: %v\n", d.flag, err)
+			return 2
+		}
+		*d.dist = dist
+	}
+	prefix := *name
+	if prefix == "" && len(*kind) > 0 {
+		prefix = "gen" + strings.ToUpper((*kind)[:1])
+	}
+	if *count > 1 {
+		if err := os.MkdirAll(*outPath, 0755); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+
+	for i := 0; i < *count; i++ {
+		opts.Seed = *seed + int64(i)
+		opts.Name = fmt.Sprintf("%s%dNoRPP", prefix, i)
+		inst, err := prpp.Generate(opts)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 2
+		}
+		write := func(f *os.File) error { return prpp.WriteInstance(f, inst) }
+		switch {
+		case *outPath == "":
+			err = write(os.Stdout)
+		case *count == 1:
+			err = writeFile(*outPath, write)
+		default:
+			err = writeFile(filepath.Join(*outPath, opts.Name), write)
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
@@ -1,17 +1,15 @@
 package main
 
 import (
-	"bufio"
+	"context"
+	"flag"
 	"fmt"
-	"math"
 	"os"
-	"sort"
+	"os/signal"
 	"strconv"
 	"time"
-	// "github.com/twmb/algoimpl/go/graph"
-	"strings"
 
-	mk "./munkres"
+	"github.com/dannodan/prpp/prpp"
 )
 
 func check(e error) {
@@ -21,156 +19,188 @@ func check(e error) {
 }
 
 func main() {
-	if len(os.Args) <= 2{
-		fmt.Println("Para ejecutar ./main <nombre-archivo> <valor-optimo>")
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "verify":
+			os.Exit(verify(os.Args[2:]))
+		case "bench":
+			os.Exit(bench(os.Args[2:]))
+		case "exact":
+			os.Exit(exact(os.Args[2:]))
+		case "model":
+			os.Exit(model(os.Args[2:]))
+		case "modelsol":
+			os.Exit(modelSolution(os.Args[2:]))
+		case "dot":
+			os.Exit(dot(os.Args[2:]))
+		case "gen":
+			os.Exit(generate(os.Args[2:]))
+		}
+	}
+	flags := flag.NewFlagSet("main", flag.ExitOnError)
+	options := solverFlags(flags)
+	progress := flags.Bool("progreso", false, "mostrar cada mejora de la solucion mientras se resuelve")
+	jsonOut := flags.Bool("json", false, "escribir tambien la solucion detallada en <nombre-archivo>-salida.json")
+	vehicles := flags.Int("vehiculos", 1, "vehiculos, cada uno con su recorrido desde el deposito")
+	budget := flags.Int("presupuesto", 0, "costo maximo del recorrido de cada vehiculo (0 sin limite)")
+	setDepot := depotFlag(flags)
+	eachDepot := flags.Bool("mejor-deposito", false, "probar cada vertice como deposito y quedarse con el mejor")
+	flags.Usage = func() {
+		fmt.Println("Para ejecutar ./main [opciones] <nombre-archivo> [<valor-optimo>]")
+		fmt.Println("Para verificar ./main verify [opciones] <nombre-archivo> <archivo-solucion>")
+		fmt.Println("Para comparar ./main bench [opciones] <carpeta>...")
+		fmt.Println("Para resolver exactamente ./main exact [opciones] <nombre-archivo>")
+		fmt.Println("Para exportar ./main model [opciones] <nombre-archivo>")
+		fmt.Println("Para leer una solucion del modelo ./main modelsol [opciones] <nombre-archivo> <archivo-solucion-mip>")
+		fmt.Println("Para dibujar ./main dot [opciones] <nombre-archivo>")
+		fmt.Println("Para generar instancias ./main gen [opciones]")
+		flags.PrintDefaults()
+	}
+	flags.Parse(os.Args[1:])
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
 		return
 	}
 
 	beginning := time.Now()
 
-	g := NewGraph()
-	positiveG := NewGraph()
-	nodes := make(map[int]Node, 0)
-	pNodes := make(map[int]Node, 0)
-	sortedEdges := Edges{}
-	sortedPositiveEdges := Edges{}
-	args := os.Args
-	file, _ := os.Open(args[1])
-	optimum, _ := strconv.ParseInt(args[2], 0, 0)
-	lineScanner := bufio.NewScanner(file)
-	line := 0
-	for lineScanner.Scan() {
-		contents := strings.Fields(lineScanner.Text())
-		if line == 0 {
-			number, _ := strconv.ParseInt(contents[len(contents)-1], 0, 0)
-			for i := 1; i < int(number+1); i++ {
-				nodes[i] = g.MakeNode()
-				*nodes[i].Value = i
-				pNodes[i] = positiveG.MakeNode()
-				*pNodes[i].Value = i
-			}
-		}
-		if _, err := strconv.Atoi(contents[0]); err == nil {
-			startNode, _ := strconv.ParseInt(contents[0], 0, 0)
-			endNode, _ := strconv.ParseInt(contents[1], 0, 0)
-			cost, _ := strconv.ParseInt(contents[2], 0, 0)
-			benefit, _ := strconv.ParseInt(contents[3], 0, 0)
-			newEdge := Edge{int(cost), int(benefit), nodes[int(startNode)], nodes[int(endNode)]}
-			newPositiveEdge := Edge{int(cost), int(benefit), pNodes[int(startNode)], pNodes[int(endNode)]}
-			sortedEdges = append(sortedEdges, newEdge)
-			sortedPositiveEdges = append(sortedPositiveEdges, newPositiveEdge)
-		}
-		line++
+	args := append([]string{os.Args[0]}, flags.Args()...)
+	inst, err := prpp.ReadInstance(args[1])
+	fileDepot := 0
+	if err == nil {
+		fileDepot = inst.Depot
+		err = setDepot(inst)
 	}
-
-	sort.Sort(sort.Reverse(sortedPositiveEdges))
-	g.GraphBuilder(sortedEdges)
-	positiveG.PositiveGraphBuilder(sortedPositiveEdges)
-	positiveG.unseeNodes()
-
-
-	// Get Floyd Warshall for the complete Graph
-	minCost, minPath := g.FloydWarshall()
-
-	// W need to connect Connected Componentes and get oddNodes
-	positiveG.LinkComponents(sortedPositiveEdges)
-
-	positiveG.unseeNodes()
-
-	// Get oddNodes
-	oddNodes := make([]int, 0) // List of OddNodes
-	for index, elem := range pNodes {
-		if positiveG.Degree(elem)%2 != 0 {
-			oddNodes = append(oddNodes, index)
-		}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
 	}
-
-	// Compute minimum Matching using Munkres Algorithm
-	// Munkres, convert matrix to single vector Munkres Algorithm for OddNodes
-	size := len(oddNodes)
-	m := mk.NewMatrix(size)
-	for i := 0; i < size; i++ {
-		for j := 0; j < size; j++ {
-			m.A[i*size+j] = int64(minCost[oddNodes[i]-1][oddNodes[j]-1])
-		}
-		m.A[i*size+i] = math.MaxInt32 // Set infinite to the same Vertice
+	optima, err := prpp.KnownOptima("")
+	check(err)
+	// The registry holds the optima from the depot of each instance file,
+	// which do not hold once -deposito or -mejor-deposito moves it.
+	optimum, known := 0, false
+	if !*eachDepot && (inst.Depot == fileDepot || fileDepot == 0 && inst.Depot == 1) {
+		optimum, known = optima[inst.Name]
 	}
-
-	minMatching := mk.ComputeMunkresMin(m)
-	newMinMatching := []mk.RowCol{}
-	minMatchMap := make(map[int]map[int]int)
-	for _, elem := range minMatching {
-		minMatchMap[oddNodes[elem.Start()]] = make(map[int]int)
-		for _ = range positiveG.nodes[elem.Start()].edges {
-			minMatchMap[oddNodes[elem.Start()]][oddNodes[elem.End()]] = 1
+	if len(args) > 2 {
+		given, err := strconv.Atoi(args[2])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "valor optimo invalido %q\n", args[2])
+			os.Exit(1)
 		}
+		optimum, known = given, true
+	}
 
+	// Ctrl-C stops the search and keeps the best solution found so far.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	opts := options()
+	if *progress {
+		opts.Progress = printProgress
 	}
-	for _, elem := range minMatching {
-		if minMatchMap[oddNodes[elem.End()]][oddNodes[elem.Start()]] != 0 {
-			minMatchMap[oddNodes[elem.Start()]][oddNodes[elem.End()]] = 0
+	if *vehicles > 1 || *budget > 0 {
+		if *eachDepot || *jsonOut {
+			fmt.Fprintln(os.Stderr, "-mejor-deposito y -json no se combinan con -vehiculos ni -presupuesto")
+			os.Exit(2)
 		}
+		os.Exit(solveFleet(ctx, args[1], inst, opts, prpp.FleetOptions{Vehicles: *vehicles, Budget: *budget}, beginning))
 	}
-
-
-	for _, elem := range minMatching {
-		if minMatchMap[oddNodes[elem.Start()]][oddNodes[elem.End()]] != 0 {
-			newMinMatching = append(newMinMatching, elem)
-
+	var solution *prpp.Solution
+	var depotValues []int
+	if *eachDepot {
+		solution, depotValues, err = prpp.SolveEachDepot(ctx, inst, opts)
+		if err == nil {
+			inst.Depot = solution.Tour[0]
 		}
+	} else {
+		solution, err = prpp.SolveContext(ctx, inst, opts)
 	}
-
-	// Insert Path from Munkres algorithm
-	for _, elem := range newMinMatching {
-		startIndex := oddNodes[elem.Start()]
-		start := nodes[startIndex].node
-		path := ReconstructPath(minPath, oddNodes[elem.Start()]-1, oddNodes[elem.End()]-1)
-		for _, vertice := range path {
-			nextIndex := vertice + 1
-			next := nodes[nextIndex].node
-			for _, edge := range start.edges {
-				if edge.end == next {
-
-					positiveG.MakeEdge(pNodes[startIndex], pNodes[nextIndex], edge.cost, edge.benefit)
-					break
-				}
-			}
-			start = next
-			startIndex = nextIndex
-
-		}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[1], err)
+		os.Exit(1)
 	}
+	value := solution.Value
 
-	eulerPath, _, value := positiveG.EulerianCycle(pNodes[1])
 	salida, err := os.Create(args[1] + "-salida.txt")
 	check(err)
-
 	defer salida.Close()
-	stringValue := strconv.Itoa(value)
-	stringPath := []string{}
-	_, err = salida.WriteString(stringValue)
-	check(err)
-	_, err = salida.WriteString("\n")
-	check(err)
-	for i := range eulerPath {
-		number := eulerPath[len(eulerPath)-i-1]
-		text := strconv.Itoa(number)
-		stringPath = append(stringPath, text)
-	}
-	result := strings.Join(stringPath, " ")
-	result = "d " + result + " d"
-	_, err = salida.WriteString(result)
-	check(err)
+	check(prpp.WriteSolution(salida, solution))
 	salida.Sync()
-
-	optimumDeviation := float64(100 * (float64(optimum) - float64(value)) / float64(optimum))
-
+	if *jsonOut {
+		check(writeJSON(args[1], inst, solution, optimum, known))
+	}
 
 	elapsed := time.Since(beginning)
 	fmt.Println()
 	fmt.Println(args[1])
-	fmt.Println("Tiempo de ejecucion: ",elapsed)
-	fmt.Println("Valor Optimo: ",optimum)
-	fmt.Println("Valor Heurística: ",value)
-	fmt.Println("Porcetanje de Desviacion: ",optimumDeviation)
+	fmt.Println("Tiempo de ejecucion: ", elapsed)
+	if known {
+		fmt.Println("Valor Optimo: ", optimum)
+	} else {
+		fmt.Println("Valor Optimo:  desconocido")
+	}
+	if *eachDepot {
+		fmt.Println("Mejor Deposito: ", inst.Depot)
+		fmt.Println("Valor por Deposito: ", depotValues)
+	}
+	fmt.Println("Valor Heurística: ", value)
+	fmt.Println("Cota Superior: ", solution.UpperBound)
+	fmt.Println("Brecha con la Cota: ", solution.Gap())
+	fmt.Println("Valor Interno Heurística: ", solution.HeuristicValue)
+	fmt.Println("Valor Recuperado en Mejora: ", solution.Recovered)
+	if solution.Iterations > 0 {
+		fmt.Println("Iteraciones GRASP: ", solution.Iterations)
+	}
+	if solution.Stopped {
+		fmt.Println("Busqueda detenida antes de terminar")
+	}
+	if known && optimum != 0 {
+		optimumDeviation := float64(100 * (float64(optimum) - float64(value)) / float64(optimum))
+		fmt.Println("Porcetanje de Desviacion: ", optimumDeviation)
+	}
+}
+
+// solveFleet solves the instance read from path for several vehicles,
+// writes its -salida.txt file with a line per route and returns the
+// process exit status.
+func solveFleet(ctx context.Context, path string, inst *prpp.Instance, opts prpp.Options, fleet prpp.FleetOptions, beginning time.Time) int {
+	solution, err := prpp.SolveFleet(ctx, inst, opts, fleet)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+		return 1
+	}
+	if err := writeFile(path+"-salida.txt", func(f *os.File) error { return prpp.WriteFleetSolution(f, solution) }); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	fmt.Println()
+	fmt.Println(path)
+	fmt.Println("Tiempo de ejecucion: ", time.Since(beginning))
+	for v, route := range solution.Routes {
+		fmt.Printf("Vehiculo %d: costo %d, valor %d\n", v+1, route.Cost, route.Value)
+	}
+	fmt.Println("Valor Heurística (conjunto): ", solution.Value)
+	fmt.Println("Cota Superior: ", solution.UpperBound)
+	if solution.Dropped > 0 {
+		fmt.Println("Lados rentables sin servir: ", solution.Dropped)
+	}
+	if solution.Stopped {
+		fmt.Println("Busqueda detenida antes de terminar")
+	}
+	return 0
+}
+
+// writeJSON writes the -salida.json file of the solution of the instance
+// read from path.
+func writeJSON(path string, inst *prpp.Instance, solution *prpp.Solution, optimum int, known bool) error {
+	return writeFile(path+"-salida.json", func(f *os.File) error {
+		return prpp.WriteSolutionJSON(f, inst, solution, optimum, known)
+	})
+}
+
+// printProgress shows an improvement of the solution on standard error.
+func printProgress(p prpp.Progress) {
+	fmt.Fprintf(os.Stderr, "mejora: valor %d a los %v (%s)\n", p.Value, p.Elapsed, p.Stage)
 }
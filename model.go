@@ -0,0 +1,106 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// model writes the integer programming model of an instance and returns
+// the process exit status.
+func model(args []string) int {
+	flags := flag.NewFlagSet("model", flag.ContinueOnError)
+	format := flags.String("formato", "lp", "formato del modelo: lp (CPLEX LP) o mps (MPS libre)")
+	outPath := flags.String("o", "", "archivo de salida; sin el, el modelo se imprime en pantalla")
+	setDepot := depotFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para exportar ./main model [opciones] <nombre-archivo>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+	var write func(f *os.File) error
+	inst, err := prpp.ReadInstance(flags.Arg(0))
+	if err == nil {
+		err = setDepot(inst)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	switch *format {
+	case "lp":
+		write = func(f *os.File) error { return prpp.WriteLP(f, inst) }
+	case "mps":
+		write = func(f *os.File) error { return prpp.WriteMPS(f, inst) }
+	default:
+		fmt.Fprintf(os.Stderr, "formato desconocido %q\n", *format)
+		return 2
+	}
+
+	if *outPath == "" {
+		err = write(os.Stdout)
+	} else {
+		err = writeFile(*outPath, write)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// modelSolution turns the solution of a MIP solver for the model of an
+// instance into a -salida.txt file and returns the process exit status.
+func modelSolution(args []string) int {
+	flags := flag.NewFlagSet("modelsol", flag.ContinueOnError)
+	setDepot := depotFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para leer una solucion del modelo ./main modelsol [opciones] <nombre-archivo> <archivo-solucion-mip>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	args = flags.Args()
+	inst, err := prpp.ReadInstance(args[0])
+	if err == nil {
+		err = setDepot(inst)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	tour, err := prpp.ReadModelSolution(inst, args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	eval, err := prpp.Evaluate(inst, tour)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", args[1], err)
+		return 1
+	}
+
+	solution := &prpp.Solution{Tour: tour, Evaluation: *eval}
+	if err := writeFile(args[0]+"-salida.txt", func(f *os.File) error { return prpp.WriteSolution(f, solution) }); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	fmt.Println(args[0] + "-salida.txt")
+	fmt.Println("Beneficio: ", eval.Benefit)
+	fmt.Println("Costo: ", eval.Cost)
+	fmt.Println("Valor: ", eval.Value)
+	return 0
+}
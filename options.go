@@ -0,0 +1,52 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// solverFlags registers the flags that tune prpp.Solve on flags and returns
+// a function that builds the options once they are parsed.
+func solverFlags(flags *flag.FlagSet) func() prpp.Options {
+	defaults := prpp.DefaultOptions()
+	prune := flags.Bool("podar", defaults.Prune, "descartar componentes que no pagan su conexion al deposito")
+	improve := flags.Bool("mejorar", defaults.Improve, "eliminar subciclos no rentables del recorrido")
+	grasp := flags.Bool("grasp", false, "usar la metaheuristica GRASP")
+	alpha := flags.Float64("alfa", 0.3, "ancho de la lista restringida de candidatos de GRASP, entre 0 y 1")
+	iterations := flags.Int("iteraciones", 100, "iteraciones de GRASP")
+	timeLimit := flags.Duration("tiempo", 0, "tiempo maximo de resolucion, por ejemplo 30s (0 sin limite); se devuelve la mejor solucion encontrada")
+	seed := flags.Int64("semilla", 1, "semilla del generador aleatorio de GRASP")
+	eulerSeed := flags.Int64("semilla-euler", 0, "semilla para elegir al azar el siguiente lado del recorrido euleriano (0 recorrido determinista)")
+	return func() prpp.Options {
+		opts := defaults
+		opts.Prune = *prune
+		opts.PruneRepeat = *prune
+		opts.Improve = *improve
+		opts.GRASP = *grasp
+		opts.Alpha = *alpha
+		opts.Iterations = *iterations
+		opts.TimeLimit = *timeLimit
+		opts.Seed = *seed
+		opts.EulerSeed = *eulerSeed
+		return opts
+	}
+}
+
+// depotFlag registers the -deposito flag on flags and returns a function
+// that, once they are parsed, moves the depot of an instance to the vertex
+// given, if any.
+func depotFlag(flags *flag.FlagSet) func(*prpp.Instance) error {
+	depot := flags.Int("deposito", 0, "vertice donde empiezan y terminan los recorridos (0 usa el de la instancia, o el 1)")
+	return func(inst *prpp.Instance) error {
+		if *depot == 0 {
+			return nil
+		}
+		if *depot < 1 || *depot > inst.Vertices {
+			return fmt.Errorf("%s: deposito %d fuera de rango 1..%d", inst.Name, *depot, inst.Vertices)
+		}
+		inst.Depot = *depot
+		return nil
+	}
+}
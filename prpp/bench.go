@@ -0,0 +1,358 @@
+package prpp
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BenchResult is the outcome of solving one instance of a batch run.
+type BenchResult struct {
+	Path       string
+	Family     string // name of the directory holding the instance
+	Name       string
+	Vertices   int
+	Edges      int
+	Value      int
+	UpperBound int
+	Gap        float64 // percentage by which Value falls short of UpperBound
+	Optimum    int
+	HasOptimum bool
+	Elapsed    time.Duration
+	Stopped    bool // the timeout or a cancellation cut the search short
+	Err        error
+}
+
+// Deviation is the percentage by which Value falls short of Optimum.
+func (r *BenchResult) Deviation() float64 {
+	return float64(100 * (float64(r.Optimum) - float64(r.Value)) / float64(r.Optimum))
+}
+
+// FamilySummary aggregates the results of one instance family.
+type FamilySummary struct {
+	Family        string
+	Instances     int
+	Failed        int
+	WithOptimum   int // solved instances whose optimum is known
+	MeanDeviation float64
+	MaxDeviation  float64
+	MeanGap       float64 // over the solved instances
+	MaxGap        float64
+	Elapsed       time.Duration
+}
+
+// FindInstances returns the instance files below the given directories,
+// sorted by path: the files whose first line is the number of vertices of
+// the NoRPP format. Hidden files, the -salida.txt and -salida.json outputs
+// and any other file, such as DOT drawings, models or bench tables, are
+// skipped.
+func FindInstances(dirs []string) ([]string, error) {
+	paths := []string{}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			name := info.Name()
+			if info.IsDir() {
+				if path != dir && strings.HasPrefix(name, ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if strings.HasPrefix(name, ".") || strings.Contains(name, "-salida.") {
+				return nil
+			}
+			if ok, err := isInstanceFile(path); err != nil || !ok {
+				return err
+			}
+			paths = append(paths, path)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// isInstanceFile tells whether the first line of the file at path that is
+// not blank is the number of vertices header of an instance.
+func isInstanceFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	lines := bufio.NewScanner(file)
+	for lines.Scan() {
+		if text := strings.TrimSpace(lines.Text()); text != "" {
+			section, err := parseHeader(text)
+			return err == nil && section == sectionVertices, nil
+		}
+	}
+	return false, nil
+}
+
+// BenchOptions configures a batch run.
+type BenchOptions struct {
+	Solver Options
+	// Workers is the number of instances solved at once, runtime.NumCPU()
+	// when zero or negative.
+	Workers int
+	// Timeout bounds the time spent on each instance when positive.
+	Timeout time.Duration
+}
+
+// ErrNotSolved is the error of the instances left when a batch run is
+// cancelled before reaching them.
+var ErrNotSolved = errors.New("not solved: batch cancelled")
+
+// Bench solves every instance in paths with a pool of workers and returns
+// the results in the order of paths. optima maps instance names to their
+// known optimal values and may be nil. Once ctx is done the instances being
+// solved stop with their best solution so far and the rest fail with
+// ErrNotSolved, so the results of a cancelled run are still complete.
+func Bench(ctx context.Context, paths []string, optima map[string]int, opts BenchOptions) []BenchResult {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	results := make([]BenchResult, len(paths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = benchOne(ctx, paths[i], optima, opts)
+			}
+		}()
+	}
+	next := 0
+feed:
+	for ; next < len(paths) && ctx.Err() == nil; next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	for i := next; i < len(paths); i++ {
+		results[i] = newBenchResult(paths[i], optima)
+		results[i].Err = ErrNotSolved
+	}
+	return results
+}
+
+func newBenchResult(path string, optima map[string]int) BenchResult {
+	r := BenchResult{
+		Path:   path,
+		Family: filepath.Base(filepath.Dir(path)),
+		Name:   filepath.Base(path),
+	}
+	r.Optimum, r.HasOptimum = optima[r.Name]
+	return r
+}
+
+func benchOne(ctx context.Context, path string, optima map[string]int, opts BenchOptions) BenchResult {
+	r := newBenchResult(path, optima)
+	beginning := time.Now()
+	inst, err := ReadInstance(path)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Vertices = inst.Vertices
+	r.Edges = len(inst.Links)
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	sol, err := SolveContext(ctx, inst, opts.Solver)
+	r.Elapsed = time.Since(beginning)
+	if err != nil {
+		r.Err = err
+		return r
+	}
+	r.Value = sol.Value
+	r.UpperBound = sol.UpperBound
+	r.Gap = sol.Gap()
+	r.Stopped = sol.Stopped
+	return r
+}
+
+// Summarize aggregates results by family, in order of first appearance.
+func Summarize(results []BenchResult) []FamilySummary {
+	summaries := []FamilySummary{}
+	position := make(map[string]int)
+	for i := range results {
+		r := &results[i]
+		p, ok := position[r.Family]
+		if !ok {
+			p = len(summaries)
+			position[r.Family] = p
+			summaries = append(summaries, FamilySummary{Family: r.Family})
+		}
+		s := &summaries[p]
+		s.Instances++
+		s.Elapsed += r.Elapsed
+		if r.Err != nil {
+			s.Failed++
+			continue
+		}
+		if r.Gap > s.MaxGap {
+			s.MaxGap = r.Gap
+		}
+		s.MeanGap += r.Gap
+		if r.HasOptimum && r.Optimum != 0 {
+			deviation := r.Deviation()
+			if s.WithOptimum == 0 || deviation > s.MaxDeviation {
+				s.MaxDeviation = deviation
+			}
+			s.MeanDeviation += deviation
+			s.WithOptimum++
+		}
+	}
+	for i := range summaries {
+		if summaries[i].WithOptimum > 0 {
+			summaries[i].MeanDeviation /= float64(summaries[i].WithOptimum)
+		}
+		if solved := summaries[i].Instances - summaries[i].Failed; solved > 0 {
+			summaries[i].MeanGap /= float64(solved)
+		}
+	}
+	return summaries
+}
+
+var benchHeader = []string{"family", "instance", "vertices", "edges", "value", "upper_bound", "gap", "optimum", "deviation", "time_ms", "stopped", "error"}
+
+func (r *BenchResult) fields() []string {
+	fields := []string{r.Family, r.Name, strconv.Itoa(r.Vertices), strconv.Itoa(r.Edges), "", "", "", "", "", "", "", ""}
+	if r.Err != nil {
+		fields[11] = r.Err.Error()
+		return fields
+	}
+	fields[4] = strconv.Itoa(r.Value)
+	fields[5] = strconv.Itoa(r.UpperBound)
+	fields[6] = strconv.FormatFloat(r.Gap, 'f', 2, 64)
+	if r.HasOptimum {
+		fields[7] = strconv.Itoa(r.Optimum)
+		if r.Optimum != 0 {
+			fields[8] = strconv.FormatFloat(r.Deviation(), 'f', 2, 64)
+		}
+	}
+	fields[9] = milliseconds(r.Elapsed)
+	if r.Stopped {
+		fields[10] = "yes"
+	}
+	return fields
+}
+
+func milliseconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds()*1000, 'f', 3, 64)
+}
+
+// WriteBenchCSV writes one row per result.
+func WriteBenchCSV(w io.Writer, results []BenchResult) error {
+	out := csv.NewWriter(w)
+	out.Write(benchHeader)
+	for i := range results {
+		out.Write(results[i].fields())
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// WriteBenchMarkdown writes a table of the results followed by a table of
+// the per-family aggregates.
+func WriteBenchMarkdown(w io.Writer, results []BenchResult) error {
+	out := bufio.NewWriter(w)
+	writeRow := func(fields []string) {
+		for i := range fields {
+			fields[i] = strings.Replace(fields[i], "|", "\\|", -1)
+		}
+		fmt.Fprintf(out, "| %s |\n", strings.Join(fields, " | "))
+	}
+	writeRule := func(columns int) {
+		fmt.Fprintf(out, "|%s\n", strings.Repeat(" --- |", columns))
+	}
+
+	writeRow(append([]string{}, benchHeader...))
+	writeRule(len(benchHeader))
+	for i := range results {
+		writeRow(results[i].fields())
+	}
+
+	fmt.Fprintln(out)
+	writeRow([]string{"family", "instances", "failed", "with_optimum", "mean_deviation", "max_deviation", "mean_gap", "max_gap", "time_ms"})
+	writeRule(9)
+	for _, s := range Summarize(results) {
+		mean, max := "", ""
+		if s.WithOptimum > 0 {
+			mean = strconv.FormatFloat(s.MeanDeviation, 'f', 2, 64)
+			max = strconv.FormatFloat(s.MaxDeviation, 'f', 2, 64)
+		}
+		meanGap, maxGap := "", ""
+		if s.Failed < s.Instances {
+			meanGap = strconv.FormatFloat(s.MeanGap, 'f', 2, 64)
+			maxGap = strconv.FormatFloat(s.MaxGap, 'f', 2, 64)
+		}
+		writeRow([]string{s.Family, strconv.Itoa(s.Instances), strconv.Itoa(s.Failed),
+			strconv.Itoa(s.WithOptimum), mean, max, meanGap, maxGap, milliseconds(s.Elapsed)})
+	}
+	return out.Flush()
+}
+
+// ReadOptima reads known optimal values from a file with one
+// "<instance-name> <value>" pair per line. Blank lines and lines starting
+// with # are ignored.
+func ReadOptima(path string) (map[string]int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return parseOptima(file, path)
+}
+
+func parseOptima(r io.Reader, name string) (map[string]int, error) {
+	optima := make(map[string]int)
+	lineScanner := bufio.NewScanner(r)
+	line := 0
+	for lineScanner.Scan() {
+		line++
+		contents := strings.Fields(lineScanner.Text())
+		if len(contents) == 0 || strings.HasPrefix(contents[0], "#") {
+			continue
+		}
+		if len(contents) != 2 {
+			return nil, &ParseError{name, line, "expected an instance name and a value"}
+		}
+		value, err := strconv.Atoi(contents[1])
+		if err != nil {
+			return nil, &ParseError{name, line, fmt.Sprintf("invalid value %q", contents[1])}
+		}
+		optima[contents[0]] = value
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, &ParseError{name, 0, err.Error()}
+	}
+	return optima, nil
+}
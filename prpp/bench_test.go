@@ -0,0 +1,115 @@
+package prpp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestFindInstancesSkipsOutputs(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"A1NoRPP":                "number of vertices : 2\nnumber of required edges 1\n1 2 1 5\nnumber of non required edges 0\n",
+		"A1NoRPP-salida.txt":     "8\nd 1 2 1 d\n",
+		"A1NoRPP-salida.json":    "{\"instance\": \"A1NoRPP\"}\n",
+		"grafo.dot":              "graph A1NoRPP {\n}\n",
+		"modelo.lp":              "\\\\ PRPP model of A1NoRPP, depot 1\nMaximize\n",
+		"resultados.csv":         "instance,vertices\n",
+		".oculto":                "number of vertices : 2\n",
+		"sub/B1NoRPP":            "\nnumber of vertices : 1\nnumber of required edges 0\nnumber of non required edges 0\n",
+		"sub/B1NoRPP-salida.txt": "0\nd 1 d\n",
+	}
+	for name, text := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(text), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	paths, err := FindInstances([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{filepath.Join(dir, "A1NoRPP"), filepath.Join(dir, "sub", "B1NoRPP")}
+	if !reflect.DeepEqual(paths, want) {
+		t.Errorf("found %v, want %v", paths, want)
+	}
+}
+
+// benchPaths lists the bundled instances of family.
+func benchPaths(t *testing.T, family string) []string {
+	t.Helper()
+	paths, err := FindInstances([]string{filepath.Join("..", "instanciasPRPP", family)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) < 4 {
+		t.Fatalf("found %d instances in %s", len(paths), family)
+	}
+	return paths
+}
+
+func TestBenchWorkers(t *testing.T) {
+	paths := benchPaths(t, "CHRISTOFIDES")
+	optima, err := KnownOptima("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	run := func(workers int) []BenchResult {
+		results := Bench(context.Background(), paths, optima, BenchOptions{Solver: DefaultOptions(), Workers: workers})
+		for i := range results {
+			results[i].Elapsed = 0
+		}
+		return results
+	}
+	one := run(1)
+	for i, r := range one {
+		if r.Path != paths[i] || r.Err != nil || r.Stopped {
+			t.Errorf("result %d: %+v", i, r)
+		}
+	}
+	if many := run(4); !reflect.DeepEqual(one, many) {
+		t.Errorf("1 worker gives %+v, 4 give %+v", one, many)
+	}
+}
+
+func TestBenchCancel(t *testing.T) {
+	paths := benchPaths(t, "CHRISTOFIDES")
+	for _, workers := range []int{1, 3} {
+		// The first solution found cancels the batch.
+		ctx, cancel := context.WithCancel(context.Background())
+		var once sync.Once
+		solver := DefaultOptions()
+		solver.Progress = func(Progress) { once.Do(cancel) }
+		results := Bench(ctx, paths, nil, BenchOptions{Solver: solver, Workers: workers})
+		cancel()
+
+		if len(results) != len(paths) {
+			t.Fatalf("%d workers: %d results for %d instances", workers, len(results), len(paths))
+		}
+		seen := make(map[string]bool)
+		solved, notSolved := 0, 0
+		for i, r := range results {
+			if r.Path != paths[i] || seen[r.Path] {
+				t.Errorf("%d workers: result %d is %s, want %s once", workers, i, r.Path, paths[i])
+			}
+			seen[r.Path] = true
+			switch {
+			case r.Err == ErrNotSolved:
+				notSolved++
+			case r.Err != nil:
+				t.Errorf("%d workers: %s: %v", workers, r.Name, r.Err)
+			default:
+				solved++
+			}
+		}
+		if solved < 1 || notSolved < 1 || solved > workers+1 {
+			t.Errorf("%d workers: %d solved and %d not after cancelling", workers, solved, notSolved)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package prpp
+
+// TrivialBound is the sum of benefit - cost over the profitable edges, an
+// upper bound on the value of any walk since every edge costs at least
+// once what it is worth. Windy edges and arcs count at their cheaper cost.
+func TrivialBound(inst *Instance) int {
+	inst = inst.undirected()
+	bound := 0
+	for _, link := range inst.Links {
+		if link.Benefit > link.Cost {
+			bound += link.Benefit - link.Cost
+		}
+	}
+	return bound
+}
+
+// UpperBound is a tighter bound than TrivialBound. The profitable edges
+// form components; the one of the depot may add all its profit, while
+// reaching any other component C takes at least two crossings of its cut
+// over unprofitable edges, each losing at least the smallest cost - benefit
+// across the cut. A crossing edge is shared by at most two components, so
+// charging every component one such loss never exceeds what the crossings
+// cost. A profitable self-loop adds its net benefit to the component of
+// its vertex. For a Directed instance it bounds the undirected relaxation.
+func UpperBound(inst *Instance) int {
+	inst = inst.undirected()
+	links, _ := boundLinks(inst)
+	gain := loopGains(inst)
+	return gain[inst.depot()-1] + cutBound(inst, links, make([]int, len(links)), gain)
+}
+
+// boundLinks splits the links of inst, parallel ones included since a walk
+// can collect each of them, into edges and self-loops, each sorted by index.
+func boundLinks(inst *Instance) (links, loops []int) {
+	for k, link := range inst.Links {
+		if link.From == link.To {
+			loops = append(loops, k)
+		} else {
+			links = append(links, k)
+		}
+	}
+	return links, loops
+}
+
+// loopGains is the net benefit of the profitable self-loops at each
+// vertex, 0-based, that a walk collects by looping once on each of them
+// when it visits it.
+func loopGains(inst *Instance) []int {
+	gain := make([]int, inst.Vertices)
+	_, loops := boundLinks(inst)
+	for _, k := range loops {
+		if link := inst.Links[k]; link.Benefit > link.Cost {
+			gain[link.From-1] += link.Benefit - link.Cost
+		}
+	}
+	return gain
+}
+
+// cutBound bounds what the undecided links, and the loops at vertices not
+// yet visited, can add to a walk over the links in state 1, which must be
+// connected and touch the depot, as UpperBound does with no link decided.
+// state holds 1 for the links in the walk, -1 for the excluded ones and 0
+// for the undecided; gain is given by loopGains.
+func cutBound(inst *Instance, links []int, state []int, gain []int) int {
+	sets := newDisjointSets(inst.Vertices)
+	for i, k := range links {
+		link := inst.Links[k]
+		if state[i] > 0 || (state[i] == 0 && link.Benefit > link.Cost) {
+			sets.union(link.From-1, link.To-1)
+		}
+	}
+	profit := make(map[int]int)
+	visited := make([]bool, inst.Vertices)
+	visited[inst.depot()-1] = true
+	for i, k := range links {
+		if link := inst.Links[k]; state[i] > 0 {
+			visited[link.From-1] = true
+			visited[link.To-1] = true
+		}
+	}
+	for v, g := range gain {
+		if g > 0 && !visited[v] {
+			profit[sets.find(v)] += g
+		}
+	}
+	loss := make(map[int]int)
+	for i, k := range links {
+		link := inst.Links[k]
+		u, v := sets.find(link.From-1), sets.find(link.To-1)
+		switch {
+		case state[i] != 0:
+		case link.Benefit > link.Cost:
+			profit[u] += link.Benefit - link.Cost
+		case u != v:
+			for _, c := range []int{u, v} {
+				if l, ok := loss[c]; !ok || link.Cost-link.Benefit < l {
+					loss[c] = link.Cost - link.Benefit
+				}
+			}
+		}
+	}
+	depot := sets.find(inst.depot() - 1)
+	bound := profit[depot]
+	for c, p := range profit {
+		if l, ok := loss[c]; ok && c != depot && p > l {
+			bound += p - l
+		}
+	}
+	return bound
+}
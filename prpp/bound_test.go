@@ -0,0 +1,62 @@
+package prpp
+
+import "testing"
+
+func TestUpperBound(t *testing.T) {
+	for _, test := range []struct {
+		name           string
+		text           string
+		trivial, bound int
+		optimum        int
+	}{
+		// 3-4 and its loop at 4 are worth 18 + 5 and reaching them from
+		// the component of the depot, worth 8, loses 5 on 2-3.
+		{"far component", `number of vertices : 4
+number of required edges 3
+1 2 2 10
+3 4 2 20
+4 4 1 6
+number of non required edges 1
+2 3 5 0
+`, 31, 26, 17},
+		// Crossing 2-3 now loses 20, more than the component is worth.
+		{"too far", `number of vertices : 4
+number of required edges 3
+1 2 2 10
+3 4 2 20
+4 4 1 6
+number of non required edges 1
+2 3 20 0
+`, 31, 11, 6},
+		// The loops at 2 and 3 join the components of their vertices; the
+		// one at 3 is the only profit of its component and 2-3 loses 1.
+		{"loops", `number of vertices : 3
+number of required edges 3
+1 2 2 10
+2 2 1 4
+3 3 1 5
+number of non required edges 1
+2 3 2 1
+`, 15, 14, 10},
+		// A loop at the depot is always collected.
+		{"depot loop", `number of vertices : 2
+number of required edges 1
+1 1 1 5
+number of non required edges 1
+1 2 3 1
+`, 4, 4, 4},
+	} {
+		inst := parseTestInstance(t, test.text)
+		trivial, bound := TrivialBound(inst), UpperBound(inst)
+		sol, finished, err := SolveExact(inst, ExactLimits{})
+		if err != nil || !finished {
+			t.Fatalf("%s: exact search finished %v: %v", test.name, finished, err)
+		}
+		if trivial != test.trivial || bound != test.bound || sol.Value != test.optimum {
+			t.Errorf("%s: trivial %d, bound %d, optimum %d, want %d, %d, %d", test.name, trivial, bound, sol.Value, test.trivial, test.bound, test.optimum)
+		}
+		if trivial < bound || bound < sol.Value {
+			t.Errorf("%s: trivial %d >= bound %d >= optimum %d does not hold", test.name, trivial, bound, sol.Value)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package prpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// solveDirected is solveOrder for Directed instances. Links that no
+// closed walk from the depot can take are not served, and every other one
+// is walked in its cheaper direction when it is served. The weak
+// components of the served arcs are linked as solveOrder does, over
+// shortest paths of arcs, and instead of matching the odd vertices the
+// graph is balanced, as many arcs in as out at every vertex, with the
+// shortest paths chosen by a minimum cost flow from the vertices with more
+// arcs in to those with more out.
+func solveDirected(ctx context.Context, inst *Instance, opts Options, order []int, serve []bool, best *incumbent) (*Solution, error) {
+	sol := &Solution{}
+
+	start := time.Now()
+	g, _ := instanceGraph(inst)
+	// Only the links on some closed walk from the depot can be served:
+	// their start is reached from the depot and their end leads back.
+	out, back := g.reachable(inst.depot()-1, false), g.reachable(inst.depot()-1, true)
+	serve = append([]bool{}, serve...)
+	for k, link := range inst.Links {
+		if serve[k] && !(out[link.From-1] && back[link.To-1]) {
+			serve[k] = false
+		}
+	}
+	positiveG, pNodes, positive := servedGraph(inst, order, serve)
+	sol.PositiveEdges = positive
+	sets := positiveG.components()
+	for v := 0; v < inst.Vertices; v++ {
+		if sets.find(v) == v {
+			sol.Components++
+		}
+	}
+	sol.track("build", start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Join the weak components, and the depot, along a minimum spanning
+	// tree of their shortest paths
+	start = time.Now()
+	_, tree := positiveG.componentTree(g, inst.depot()-1)
+	for _, l := range tree {
+		from := l.path[0]
+		for _, next := range l.path[1:] {
+			e := cheapestEdge(g, from, next)
+			positiveG.MakeArc(pNodes[from+1], pNodes[next+1], e.cost, e.benefit)
+			sol.Connectors = append(sol.Connectors, [2]int{from + 1, next + 1})
+			from = next
+		}
+	}
+	sol.track("link-components", start)
+
+	start = time.Now()
+	surplus := make([]int, inst.Vertices)
+	sources := []int{}
+	for index := 1; index < inst.Vertices+1; index++ {
+		surplus[index-1] = positiveG.InDegree(pNodes[index]) - positiveG.OutDegree(pNodes[index])
+		if surplus[index-1] != 0 {
+			sol.OddNodes++
+		}
+		if surplus[index-1] > 0 {
+			sources = append(sources, index-1)
+		}
+	}
+	sol.track("unbalanced", start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	paths := NewShortestPaths(g)
+	paths.Prepare(sources)
+	sol.track("shortest-paths", start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	transfers := balanceDegrees(surplus, paths.Dist)
+	sol.track("min-cost-flow", start)
+
+	// Insert copies of the shortest paths chosen by the flow
+	start = time.Now()
+	for _, t := range transfers {
+		path := paths.Path(t.from, t.to)
+		for c := 0; c < t.copies; c++ {
+			from := t.from
+			balancingPath := []int{from + 1}
+			for _, next := range path {
+				e := cheapestEdge(g, from, next)
+				positiveG.MakeArc(pNodes[from+1], pNodes[next+1], e.cost, e.benefit)
+				from = next
+				balancingPath = append(balancingPath, next+1)
+			}
+			sol.MatchingPaths = append(sol.MatchingPaths, balancingPath)
+			sol.MatchedPairs++
+		}
+	}
+
+	arcs := 0
+	for _, ends := range positiveG.ends {
+		if ends[0] != nil {
+			arcs++
+		}
+	}
+	walk, ok := positiveG.DirectedEulerianCycleEdges(pNodes[inst.depot()], opts.eulerRand())
+	if !ok {
+		return nil, errors.New("directed graph is not balanced after the minimum cost flow; is the instance strongly connected?")
+	}
+	if len(walk) != arcs {
+		return nil, fmt.Errorf("euler tour walks %d of %d arcs; is the instance strongly connected?", len(walk), arcs)
+	}
+	sol.Tour = positiveG.WalkVertices(pNodes[inst.depot()], walk)
+	sol.HeuristicValue = positiveG.WalkValue(walk)
+	sol.track("euler", start)
+
+	return finishTour(ctx, inst, opts, sol, best)
+}
@@ -0,0 +1,67 @@
+package prpp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// WriteDOT draws inst as a GraphViz graph. Every edge is labeled with its
+// cost and benefit, and the profitable ones (benefit >= cost, the edges
+// PositiveGraphBuilder keeps) are drawn bold. Windy edges show both costs,
+// forward/backward, and arcs an arrow. When s is not nil the stages
+// of the solution are drawn on top: the edges walked by the tour in red
+// with their traversal count, the connectors that link the components as
+// blue dashed edges and the matching paths as numbered orange dotted
+// edges, and the depot as a double circle.
+func WriteDOT(w io.Writer, inst *Instance, s *Solution) error {
+	counts := make(map[int]int)
+	depot := inst.depot()
+	if s != nil {
+		for _, t := range s.Edges {
+			counts[t.Index] = t.Count
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "graph %q {\n", inst.Name)
+	fmt.Fprintln(out, "  node [shape=circle];")
+	for v := 1; v <= inst.Vertices; v++ {
+		if v == depot {
+			fmt.Fprintf(out, "  %d [shape=doublecircle];\n", v)
+		} else {
+			fmt.Fprintf(out, "  %d;\n", v)
+		}
+	}
+	for k, link := range inst.Links {
+		cost := fmt.Sprintf("c%d", link.Cost)
+		if !link.OneWay && link.Back != link.Cost {
+			cost = fmt.Sprintf("c%d/%d", link.Cost, link.Back)
+		}
+		attrs := fmt.Sprintf("label=\"%s b%d\"", cost, link.Benefit)
+		if link.Benefit >= link.minCost() {
+			attrs += ", style=bold"
+		} else {
+			attrs += ", color=gray"
+		}
+		if count := counts[k]; count > 0 {
+			attrs = fmt.Sprintf("label=\"%s b%d x%d\", color=red, penwidth=%d", cost, link.Benefit, count, 1+count)
+		}
+		if link.OneWay {
+			attrs += ", dir=forward"
+		}
+		fmt.Fprintf(out, "  %d -- %d [%s];\n", link.From, link.To, attrs)
+	}
+	if s != nil {
+		for _, c := range s.Connectors {
+			fmt.Fprintf(out, "  %d -- %d [color=blue, style=dashed, constraint=false];\n", c[0], c[1])
+		}
+		for i, path := range s.MatchingPaths {
+			for j := 1; j < len(path); j++ {
+				fmt.Fprintf(out, "  %d -- %d [color=orange, style=dotted, constraint=false, label=\"m%d\"];\n", path[j-1], path[j], i+1)
+			}
+		}
+	}
+	fmt.Fprintln(out, "}")
+	return out.Flush()
+}
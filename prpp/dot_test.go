@@ -0,0 +1,64 @@
+package prpp
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	// 1-2 is served, 2-3 is windy, costing 1 from 2 and 3 from 3, and
+	// 3-1 is a deadhead.
+	inst := parseTestInstance(t, `number of vertices : 3
+number of required edges 2
+1 2 2 10
+2 3 1 4 3
+number of non required edges 1
+3 1 3 0
+`)
+	inst.Name = "tres"
+	walk := []int{1, 2, 3, 1, 3, 1}
+	eval, err := Evaluate(inst, walk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sol := &Solution{Tour: walk, Evaluation: *eval}
+	sol.Connectors = [][2]int{{3, 1}}
+	sol.MatchingPaths = [][]int{{1, 3}}
+
+	for _, test := range []struct {
+		name string
+		sol  *Solution
+		want string
+	}{
+		{"instance", nil, `graph "tres" {
+  node [shape=circle];
+  1 [shape=doublecircle];
+  2;
+  3;
+  1 -- 2 [label="c2 b10", style=bold];
+  2 -- 3 [label="c1/3 b4", style=bold];
+  3 -- 1 [label="c3 b0", color=gray];
+}
+`},
+		{"solution", sol, `graph "tres" {
+  node [shape=circle];
+  1 [shape=doublecircle];
+  2;
+  3;
+  1 -- 2 [label="c2 b10 x1", color=red, penwidth=2];
+  2 -- 3 [label="c1/3 b4 x1", color=red, penwidth=2];
+  3 -- 1 [label="c3 b0 x3", color=red, penwidth=4];
+  3 -- 1 [color=blue, style=dashed, constraint=false];
+  1 -- 3 [color=orange, style=dotted, constraint=false, label="m1"];
+}
+`},
+	} {
+		var buf bytes.Buffer
+		if err := WriteDOT(&buf, inst, test.sol); err != nil {
+			t.Fatal(err)
+		}
+		if buf.String() != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.name, buf.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package prpp
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Traversal tells how many times a walk uses one edge of the instance,
+// the benefit collected on the first of them and what they cost, each in
+// the direction it was walked.
+type Traversal struct {
+	Link    Link
+	Index   int // position of Link in Instance.Links
+	Count   int
+	Benefit int
+	Cost    int
+}
+
+// Evaluation is the PRPP objective of a walk: the benefit of an edge is
+// collected on its first traversal only, its cost is paid on every one.
+type Evaluation struct {
+	Benefit int
+	Cost    int
+	Value   int
+	// Edges lists the edges walked at least once, in order of first use.
+	Edges []Traversal
+}
+
+// walkLinks returns the index of the link used by each step of walk,
+// telling apart parallel links. The steps between two vertices, in one
+// direction when inst is Directed, are spread over the links between them:
+// with t such steps and c the cost of the cheapest link, walking link k
+// instead is worth its benefit - cost + c, so the t links worth the most,
+// if positive, take the first of those steps, each once, and the rest walk
+// the cheapest link, among the cheapest the most profitable. Without
+// parallel links every step uses the one link between its vertices.
+func walkLinks(inst *Instance, walk []int) ([]int, error) {
+	directed := inst.Directed()
+	key := func(u, v int) [2]int {
+		if directed {
+			return [2]int{u, v}
+		}
+		return pairKey(u, v)
+	}
+	candidates := make(map[[2]int][]int, len(inst.Links))
+	for k, link := range inst.Links {
+		candidates[key(link.From, link.To)] = append(candidates[key(link.From, link.To)], k)
+		if directed && !link.OneWay && link.From != link.To {
+			candidates[key(link.To, link.From)] = append(candidates[key(link.To, link.From)], k)
+		}
+	}
+
+	steps := make(map[[2]int][]int)
+	order := [][2]int{}
+	for i := 1; i < len(walk); i++ {
+		u, v := walk[i-1], walk[i]
+		pair := key(u, v)
+		if _, ok := candidates[pair]; !ok {
+			return nil, fmt.Errorf("step %d: no edge from %d to %d", i, u, v)
+		}
+		if _, ok := steps[pair]; !ok {
+			order = append(order, pair)
+		}
+		steps[pair] = append(steps[pair], i-1)
+	}
+
+	links := make([]int, max(len(walk)-1, 0))
+	for _, pair := range order {
+		from := walk[steps[pair][0]]
+		ks := append([]int{}, candidates[pair]...)
+		cheapest := ks[0]
+		for _, k := range ks[1:] {
+			cost, best := inst.Links[k].stepCost(from), inst.Links[cheapest].stepCost(from)
+			if cost < best || (cost == best && inst.Links[k].Benefit > inst.Links[cheapest].Benefit) {
+				cheapest = k
+			}
+		}
+		c := inst.Links[cheapest].stepCost(from)
+		worth := func(k int) int {
+			return inst.Links[k].Benefit - inst.Links[k].stepCost(from) + c
+		}
+		sort.SliceStable(ks, func(a, b int) bool {
+			return worth(ks[a]) > worth(ks[b])
+		})
+		for n, step := range steps[pair] {
+			if n < len(ks) && worth(ks[n]) > 0 {
+				links[step] = ks[n]
+			} else {
+				links[step] = cheapest
+			}
+		}
+	}
+	return links, nil
+}
+
+func pairKey(u, v int) [2]int {
+	if u > v {
+		u, v = v, u
+	}
+	return [2]int{u, v}
+}
+
+// Evaluate computes the objective of walk, a sequence of vertex ids in which
+// every consecutive pair must be joined by an edge of inst that can be
+// walked in that direction. Parallel edges are told apart as walkLinks
+// does, so Edges counts the traversals of each one.
+func Evaluate(inst *Instance, walk []int) (*Evaluation, error) {
+	links, err := walkLinks(inst, walk)
+	if err != nil {
+		return nil, err
+	}
+	eval := &Evaluation{}
+	position := make(map[int]int)
+	for i, k := range links {
+		u := walk[i]
+		link := inst.Links[k]
+		p, ok := position[k]
+		if !ok {
+			p = len(eval.Edges)
+			position[k] = p
+			eval.Edges = append(eval.Edges, Traversal{Link: link, Index: k, Benefit: link.Benefit})
+			eval.Benefit += link.Benefit
+		}
+		eval.Edges[p].Count++
+		eval.Edges[p].Cost += link.stepCost(u)
+		eval.Cost += link.stepCost(u)
+	}
+	eval.Value = eval.Benefit - eval.Cost
+	return eval, nil
+}
+
+// Verify checks that walk is a closed walk from depot over the edges of inst
+// and that its objective equals the declared value. The evaluation is
+// returned whenever the walk follows edges of inst, even if it is not
+// closed or the values differ.
+func Verify(inst *Instance, walk []int, depot, declared int) (*Evaluation, error) {
+	if len(walk) == 0 {
+		return nil, errors.New("empty walk")
+	}
+	eval, err := Evaluate(inst, walk)
+	if err != nil {
+		return nil, err
+	}
+	if walk[0] != depot || walk[len(walk)-1] != depot {
+		return eval, fmt.Errorf("walk goes from %d to %d, not closed at depot %d", walk[0], walk[len(walk)-1], depot)
+	}
+	if eval.Value != declared {
+		return eval, fmt.Errorf("declared value %d, recomputed %d", declared, eval.Value)
+	}
+	return eval, nil
+}
+
+// EvaluateFleet computes the combined objective of several walks: the
+// benefit of an edge is collected once even if more than one walk uses it,
+// and the cost of every traversal of every walk is paid. Edges lists them
+// in order of first use, walk after walk, with their total traversals.
+func EvaluateFleet(inst *Instance, walks [][]int) (*Evaluation, error) {
+	combined := &Evaluation{}
+	position := make(map[int]int)
+	for w, walk := range walks {
+		eval, err := Evaluate(inst, walk)
+		if err != nil {
+			return nil, fmt.Errorf("walk %d: %v", w+1, err)
+		}
+		combined.Cost += eval.Cost
+		for _, t := range eval.Edges {
+			p, ok := position[t.Index]
+			if !ok {
+				p = len(combined.Edges)
+				position[t.Index] = p
+				combined.Edges = append(combined.Edges, Traversal{Link: t.Link, Index: t.Index, Benefit: t.Benefit})
+				combined.Benefit += t.Benefit
+			}
+			combined.Edges[p].Count += t.Count
+			combined.Edges[p].Cost += t.Cost
+		}
+	}
+	combined.Value = combined.Benefit - combined.Cost
+	return combined, nil
+}
+
+// VerifyFleet is Verify for the walks of several vehicles, all closed at
+// depot, whose combined objective must equal the declared value.
+func VerifyFleet(inst *Instance, walks [][]int, depot, declared int) (*Evaluation, error) {
+	if len(walks) == 0 {
+		return nil, errors.New("no walks")
+	}
+	eval, err := EvaluateFleet(inst, walks)
+	if err != nil {
+		return nil, err
+	}
+	for w, walk := range walks {
+		if len(walk) == 0 {
+			return eval, fmt.Errorf("walk %d is empty", w+1)
+		}
+		if walk[0] != depot || walk[len(walk)-1] != depot {
+			return eval, fmt.Errorf("walk %d goes from %d to %d, not closed at depot %d", w+1, walk[0], walk[len(walk)-1], depot)
+		}
+	}
+	if eval.Value != declared {
+		return eval, fmt.Errorf("declared value %d, recomputed %d", declared, eval.Value)
+	}
+	return eval, nil
+}
@@ -0,0 +1,199 @@
+package prpp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const squareInstance = `number of vertices : 4
+number of required edges 2
+1 2 2 10
+2 3 3 4
+number of non required edges 2
+3 1 1 0
+3 4 5 1
+`
+
+func TestVerify(t *testing.T) {
+	inst := parseTestInstance(t, squareInstance)
+	for _, test := range []struct {
+		name     string
+		walk     []int
+		depot    int
+		declared int
+		err      string // empty when valid
+		value    int    // recomputed value, when the walk follows edges
+	}{
+		{"valid", []int{1, 2, 3, 1}, 1, 8, "", 8},
+		{"reversed", []int{1, 3, 2, 1}, 1, 8, "", 8},
+		{"stays home", []int{1}, 1, 0, "", 0},
+		{"other depot", []int{3, 4, 3}, 3, -9, "", -9},
+		{"wrong value", []int{1, 2, 3, 1}, 1, 9, "declared value 9, recomputed 8", 8},
+		{"not closed", []int{1, 2, 3}, 1, 9, "walk goes from 1 to 3", 9},
+		{"wrong depot", []int{1, 2, 3, 1}, 2, 8, "not closed at depot 2", 8},
+		{"missing edge", []int{1, 4, 3, 1}, 1, 0, "step 1: no edge from 1 to 4", 0},
+		{"empty", []int{}, 1, 0, "empty walk", 0},
+	} {
+		eval, err := Verify(inst, test.walk, test.depot, test.declared)
+		if test.err == "" {
+			if err != nil {
+				t.Errorf("%s: %v", test.name, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: error %v, want %q", test.name, err, test.err)
+		}
+		if eval != nil && eval.Value != test.value {
+			t.Errorf("%s: recomputed %d, want %d", test.name, eval.Value, test.value)
+		}
+	}
+}
+
+func TestVerifySolutionFile(t *testing.T) {
+	dir := t.TempDir()
+	for _, test := range []struct{ family, name string }{
+		{"CHRISTOFIDES", "P01NoRPP"},
+		{"DEGREE", "D0NoRPP"},
+		{"GRID", "G0NoRPP"},
+		{"RANDOM", "R0NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		sol, err := Solve(inst, DefaultOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, test.name+"-salida.txt")
+		file, err := os.Create(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := WriteSolution(file, sol); err != nil {
+			t.Fatal(err)
+		}
+		file.Close()
+		declared, walk, err := ReadSolution(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(inst, walk, inst.depot(), declared); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestVerifyFleet(t *testing.T) {
+	inst := parseTestInstance(t, squareInstance)
+	// 1-2 is served by both walks but collected once.
+	walks := [][]int{{1, 2, 1}, {1, 2, 3, 1}, {1}}
+	eval, err := VerifyFleet(inst, walks, 1, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eval.Benefit != 14 || eval.Cost != 10 {
+		t.Errorf("benefit %d and cost %d, want 14 and 10", eval.Benefit, eval.Cost)
+	}
+	if _, err := VerifyFleet(inst, [][]int{{1, 2, 1}, {3, 1, 3}}, 1, 10); err == nil || !strings.Contains(err.Error(), "walk 2") {
+		t.Errorf("error %v for a walk away from the depot", err)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	square := parseTestInstance(t, squareInstance)
+	parallel := parseTestInstance(t, `number of vertices : 3
+number of required edges 2
+1 2 1 10
+1 2 3 10
+number of non required edges 2
+2 3 1 0
+3 3 1 5
+`)
+	windy := parseTestInstance(t, `number of vertices : 3
+number of required edges 2
+1 2 2 10 5
+2 3 1 4 -
+number of non required edges 1
+3 1 3 0 1
+`)
+	for _, test := range []struct {
+		name                 string
+		inst                 *Instance
+		walk                 []int
+		benefit, cost, value int
+		counts               map[int]int // traversals by link index
+	}{
+		{"once", square, []int{1, 2, 3, 1}, 14, 6, 8, map[int]int{0: 1, 1: 1, 2: 1}},
+		{"twice", square, []int{1, 2, 1, 2, 1}, 10, 8, 2, map[int]int{0: 4}},
+		{"detour", square, []int{1, 2, 3, 4, 3, 1}, 15, 16, -1, map[int]int{0: 1, 1: 1, 3: 2, 2: 1}},
+		{"parallel", parallel, []int{1, 2, 1}, 20, 4, 16, map[int]int{0: 1, 1: 1}},
+		{"parallel thrice", parallel, []int{1, 2, 1, 2, 1}, 20, 6, 14, map[int]int{0: 3, 1: 1}},
+		{"loop", parallel, []int{1, 2, 3, 3, 2, 1}, 25, 7, 18, map[int]int{0: 1, 1: 1, 2: 2, 3: 1}},
+		{"windy", windy, []int{1, 2, 3, 1}, 14, 6, 8, map[int]int{0: 1, 1: 1, 2: 1}},
+		{"windy back", windy, []int{1, 3, 1}, 0, 4, -4, map[int]int{2: 2}},
+		{"windy other way", windy, []int{2, 1, 2}, 10, 7, 3, map[int]int{0: 2}},
+	} {
+		eval, err := Evaluate(test.inst, test.walk)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if eval.Benefit != test.benefit || eval.Cost != test.cost || eval.Value != test.value {
+			t.Errorf("%s: benefit %d, cost %d, value %d, want %d, %d, %d", test.name, eval.Benefit, eval.Cost, eval.Value, test.benefit, test.cost, test.value)
+		}
+		counts := make(map[int]int)
+		for _, e := range eval.Edges {
+			counts[e.Index] = e.Count
+			if e.Link != test.inst.Links[e.Index] {
+				t.Errorf("%s: edge %d holds %+v", test.name, e.Index, e.Link)
+			}
+		}
+		if len(counts) != len(test.counts) {
+			t.Errorf("%s: traversals %v, want %v", test.name, counts, test.counts)
+		}
+		for k, count := range test.counts {
+			if counts[k] != count {
+				t.Errorf("%s: traversals %v, want %v", test.name, counts, test.counts)
+				break
+			}
+		}
+	}
+
+	if _, err := Evaluate(windy, []int{3, 2}); err == nil {
+		t.Error("walked an arc against its direction")
+	}
+}
+
+func TestSolveBundledOptima(t *testing.T) {
+	optima, err := KnownOptima("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct{ family, name string }{
+		{"CHRISTOFIDES", "P01NoRPP"},
+		{"CHRISTOFIDES", "P05NoRPP"},
+		{"DEGREE", "D0NoRPP"},
+		{"DEGREE", "D5NoRPP"},
+		{"GRID", "G0NoRPP"},
+		{"RANDOM", "R0NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		optimum, ok := optima[test.name]
+		if !ok {
+			t.Fatalf("%s: no optimum in optima.txt", test.name)
+		}
+		sol, err := Solve(inst, DefaultOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		eval, err := Evaluate(inst, sol.Tour)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if eval.Value != sol.Value {
+			t.Errorf("%s: solution value %d, tour worth %d", test.name, sol.Value, eval.Value)
+		}
+		if sol.Value > optimum || optimum > sol.UpperBound {
+			t.Errorf("%s: value %d, optimum %d, bound %d out of order", test.name, sol.Value, optimum, sol.UpperBound)
+		}
+	}
+}
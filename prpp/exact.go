@@ -0,0 +1,304 @@
+package prpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dannodan/prpp/blossom"
+)
+
+// ExactLimits stops SolveExact early. Zero values mean no limit.
+type ExactLimits struct {
+	Nodes     int
+	TimeLimit time.Duration
+}
+
+// SolveExact finds an optimal closed walk from the depot by branch and bound.
+//
+// Every closed walk traverses a connected set T of edges that touches the
+// depot, and the cheapest walk over exactly T costs c(T) plus a minimum
+// T-join of the odd vertices of T inside T, so the optimum is the best
+// b(T) - c(T) - join(T) over those sets, plus the profitable self-loops at
+// the vertices of T, each walked once. The search grows T one edge at a
+// time from the depot, branching on whether an edge next to T joins it, and
+// prunes a node when b(T) - c(T) plus a bound on what the undecided edges
+// can add cannot beat the best walk found, starting with the one found by
+// Solve. Parallel links are separate edges, so a walk may collect each.
+//
+// The second result tells whether the search finished, so the value of the
+// walk is proven optimal; when a limit stops it first the best walk found
+// is returned. HeuristicValue holds the value of the walk found by Solve.
+// Directed instances are not supported.
+func SolveExact(inst *Instance, limits ExactLimits) (*Solution, bool, error) {
+	return SolveExactContext(context.Background(), inst, limits, nil)
+}
+
+// SolveExactContext is SolveExact stopping early, like the limits do, once
+// ctx is done. progress, if not nil, is called with every new best walk.
+func SolveExactContext(ctx context.Context, inst *Instance, limits ExactLimits, progress func(Progress)) (*Solution, bool, error) {
+	if inst.Vertices < 1 {
+		return nil, false, errors.New("instance has no vertices")
+	}
+	if inst.Directed() {
+		return nil, false, errors.New("branch and bound only solves undirected instances")
+	}
+	if limits.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.TimeLimit)
+		defer cancel()
+	}
+	best := newIncumbent(progress)
+	opts := DefaultOptions()
+	opts.Progress = progress
+	heuristic, err := SolveContext(ctx, inst, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	if heuristic.Stopped {
+		return heuristic, false, nil
+	}
+	best.best = heuristic
+
+	s := &exactSearch{ctx: ctx, inst: inst, nodeLimit: limits.Nodes, incumbent: best}
+	s.links, _ = boundLinks(inst)
+	s.state = make([]int, len(s.links))
+	s.degree = make([]int, inst.Vertices)
+	s.gain = loopGains(inst)
+	s.net = s.gain[inst.depot()-1]
+	if err := s.offer(nil); err != nil {
+		return nil, false, err
+	}
+
+	start := time.Now()
+	s.search()
+	if s.err != nil {
+		return nil, false, s.err
+	}
+
+	sol := &Solution{Tour: best.best.Tour, HeuristicValue: heuristic.Value, UpperBound: heuristic.UpperBound, Evaluation: best.best.Evaluation}
+	if !s.stopped {
+		sol.UpperBound = sol.Value
+	}
+	sol.Nodes = s.nodes
+	sol.Stopped = s.stopped
+	sol.Stages = append(heuristic.Stages, Stage{"branch-and-bound", time.Since(start)})
+	return sol, !s.stopped, nil
+}
+
+// exactSearch is the state of the branch and bound of SolveExact. Edges
+// are numbered by their position in links.
+type exactSearch struct {
+	ctx    context.Context
+	inst   *Instance
+	links  []int // indices in inst.Links of the edges searched
+	state  []int // 1 in T, -1 excluded, 0 undecided
+	degree []int // degree of each vertex in T
+	gain   []int // loopGains of the instance
+	size   int   // edges in T
+	net    int   // b(T) - c(T) plus the loops at the vertices of T
+
+	incumbent *incumbent
+	nodes     int
+	nodeLimit int
+	stopped   bool
+	err       error
+}
+
+func (s *exactSearch) search() {
+	if s.stopped || s.err != nil {
+		return
+	}
+	s.nodes++
+	if (s.nodeLimit > 0 && s.nodes > s.nodeLimit) || (s.nodes%64 == 0 && s.ctx.Err() != nil) {
+		s.stopped = true
+		return
+	}
+
+	// Branch on the most profitable undecided edge touching T.
+	next, nextNet := -1, 0
+	depot := s.inst.depot()
+	for i, state := range s.state {
+		link := s.inst.Links[s.links[i]]
+		touches := s.degree[link.From-1] > 0 || s.degree[link.To-1] > 0 || link.From == depot || link.To == depot
+		if state == 0 && touches && (next < 0 || link.Benefit-link.Cost > nextNet) {
+			next, nextNet = i, link.Benefit-link.Cost
+		}
+	}
+	if next < 0 {
+		return
+	}
+	if s.bound() <= s.incumbent.best.Value {
+		return
+	}
+
+	s.include(next, 1)
+	if s.net > s.incumbent.best.Value {
+		s.evaluate()
+	}
+	s.search()
+	s.include(next, -1)
+
+	s.state[next] = -1
+	s.search()
+	s.state[next] = 0
+}
+
+// bound is an upper bound on the value of any extension of T.
+func (s *exactSearch) bound() int {
+	return s.net + cutBound(s.inst, s.links, s.state, s.gain)
+}
+
+// include adds edge i to T when sign is 1 and takes it out when it is -1.
+func (s *exactSearch) include(i, sign int) {
+	link := s.inst.Links[s.links[i]]
+	s.state[i] = (sign + 1) / 2
+	for _, v := range []int{link.From - 1, link.To - 1} {
+		s.degree[v] += sign
+		// The loops at v count while v is in T.
+		if v != s.inst.depot()-1 && s.degree[v] == (sign+1)/2 {
+			s.net += sign * s.gain[v]
+		}
+	}
+	s.size += sign
+	s.net += sign * (link.Benefit - link.Cost)
+}
+
+// evaluate offers the cheapest walk over T if it can beat the best one.
+func (s *exactSearch) evaluate() {
+	set := make([]int, 0, s.size)
+	for i, state := range s.state {
+		if state > 0 {
+			set = append(set, s.links[i])
+		}
+	}
+	odd := false
+	for _, d := range s.degree {
+		odd = odd || d%2 != 0
+	}
+	join := 0
+	if odd {
+		var err error
+		join, _, err = edgeSetWalk(s.inst, set, s.inst.depot(), false)
+		if err != nil {
+			s.err = err
+			return
+		}
+	}
+	if s.net-join > s.incumbent.best.Value {
+		s.err = s.offer(set)
+	}
+}
+
+// offer builds the walk over the links in set, with the profitable loops at
+// the vertices it visits, and offers it to the incumbent.
+func (s *exactSearch) offer(set []int) error {
+	tour := []int{s.inst.depot()}
+	if len(set) > 0 {
+		var err error
+		_, tour, err = edgeSetWalk(s.inst, set, s.inst.depot(), true)
+		if err != nil {
+			return err
+		}
+	}
+	loops := make([]int, s.inst.Vertices)
+	_, selfLoops := boundLinks(s.inst)
+	for _, k := range selfLoops {
+		if link := s.inst.Links[k]; link.Benefit > link.Cost {
+			loops[link.From-1]++
+		}
+	}
+	walked := make([]int, 0, len(tour))
+	for _, v := range tour {
+		walked = append(walked, v)
+		// Every profitable loop at v, the first time the walk meets it.
+		for ; loops[v-1] > 0; loops[v-1]-- {
+			walked = append(walked, v)
+		}
+	}
+	eval, err := Evaluate(s.inst, walked)
+	if err != nil {
+		return fmt.Errorf("branch and bound walk is not a walk of the instance: %v", err)
+	}
+	s.incumbent.offer(&Solution{Tour: walked, Evaluation: *eval}, "branch-and-bound")
+	return nil
+}
+
+// edgeSetWalk returns the cost of the minimum T-join of the odd vertices
+// of the links in set, using only those links, and with walk the closed
+// walk from depot that traverses every link of set once plus the join.
+// The links must be connected and touch the depot.
+func edgeSetWalk(inst *Instance, set []int, depot int, walk bool) (int, []int, error) {
+	g := NewGraph()
+	nodes := make(map[int]Node, inst.Vertices)
+	for i := 1; i < inst.Vertices+1; i++ {
+		nodes[i] = g.MakeNode()
+		*nodes[i].Value = i
+	}
+	edges := Edges{}
+	for _, k := range set {
+		link := inst.Links[k]
+		edges = append(edges, Edge{link.Cost, link.Benefit, nodes[link.From], nodes[link.To]})
+	}
+	g.GraphBuilder(edges)
+
+	oddNodes := []int{}
+	sources := []int{}
+	for i := 1; i < inst.Vertices+1; i++ {
+		if g.Degree(nodes[i])%2 != 0 {
+			oddNodes = append(oddNodes, i)
+			sources = append(sources, i-1)
+		}
+	}
+	paths := NewShortestPaths(g)
+	paths.Prepare(sources)
+	size := len(oddNodes)
+	m := blossom.NewMatrix(size)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			m.A[i*size+j] = int64(paths.Dist(oddNodes[i]-1, oddNodes[j]-1))
+		}
+	}
+	matching, err := blossom.ComputeMinPerfect(m)
+	if err != nil {
+		return 0, nil, err
+	}
+	join := 0
+	for _, pair := range matching {
+		join += paths.Dist(oddNodes[pair.Start()]-1, oddNodes[pair.End()]-1)
+	}
+	if !walk {
+		return join, nil, nil
+	}
+
+	// Walk a copy of the set with the join added, since the paths must be
+	// read from the unchanged graph.
+	tg := NewGraph()
+	tNodes := make(map[int]Node, inst.Vertices)
+	for i := 1; i < inst.Vertices+1; i++ {
+		tNodes[i] = tg.MakeNode()
+		*tNodes[i].Value = i
+	}
+	for _, k := range set {
+		link := inst.Links[k]
+		tg.MakeEdge(tNodes[link.From], tNodes[link.To], link.Cost, link.Benefit)
+	}
+	for _, pair := range matching {
+		from := oddNodes[pair.Start()]
+		path := paths.Path(from-1, oddNodes[pair.End()]-1)
+		if path == nil {
+			return 0, nil, fmt.Errorf("no path between odd vertices %d and %d", from, oddNodes[pair.End()])
+		}
+		for _, vertice := range path {
+			edge := cheapestEdge(g, from-1, vertice)
+			tg.MakeEdge(tNodes[from], tNodes[vertice+1], edge.cost, edge.benefit)
+			from = vertice + 1
+		}
+	}
+	ids, ok := tg.EulerianCycleEdges(tNodes[depot], nil)
+	if !ok {
+		return 0, nil, errors.New("edge set has odd degree vertices after the join")
+	}
+	return join, tg.WalkVertices(tNodes[depot], ids), nil
+}
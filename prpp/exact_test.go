@@ -0,0 +1,142 @@
+package prpp
+
+import "testing"
+
+func TestSolveExactBundled(t *testing.T) {
+	optima, err := KnownOptima("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, test := range []struct{ family, name string }{
+		{"CHRISTOFIDES", "P01NoRPP"},
+		{"CHRISTOFIDES", "P10NoRPP"},
+		{"CHRISTOFIDES", "P11NoRPP"},
+		{"CHRISTOFIDES", "P12NoRPP"},
+		{"CHRISTOFIDES", "P13NoRPP"},
+		{"DEGREE", "D0NoRPP"},
+		{"GRID", "G1NoRPP"},
+		{"GRID", "G5NoRPP"},
+		{"GRID", "G8NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		sol, proven, err := SolveExact(inst, ExactLimits{Nodes: 1000000})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !proven {
+			t.Errorf("%s: search stopped after %d nodes", test.name, sol.Nodes)
+			continue
+		}
+		if sol.Value != optima[test.name] {
+			t.Errorf("%s: optimum %d, optima.txt has %d", test.name, sol.Value, optima[test.name])
+		}
+		if _, err := Verify(inst, sol.Tour, inst.depot(), sol.Value); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if sol.HeuristicValue > sol.Value || sol.Value > UpperBound(inst) {
+			t.Errorf("%s: heuristic %d, optimum %d, bound %d out of order", test.name, sol.HeuristicValue, sol.Value, UpperBound(inst))
+		}
+	}
+}
+
+func TestSolveExactSmall(t *testing.T) {
+	for _, test := range []struct {
+		name    string
+		text    string
+		optimum int
+	}{
+		{"square", squareInstance, 8},
+		{"parallel and loop", `number of vertices : 3
+number of required edges 2
+1 2 1 10
+1 2 3 10
+number of non required edges 2
+2 3 1 0
+3 3 1 5
+`, 18},
+		{"other depot", `number of vertices : 4
+depot : 4
+number of required edges 1
+1 2 1 15
+number of non required edges 2
+3 4 2 0
+1 3 2 0
+`, 5},
+		{"nothing worth it", `number of vertices : 3
+number of required edges 1
+2 3 1 10
+number of non required edges 1
+1 2 5 0
+`, 0},
+	} {
+		inst := parseTestInstance(t, test.text)
+		sol, proven, err := SolveExact(inst, ExactLimits{})
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !proven || sol.Value != test.optimum {
+			t.Errorf("%s: value %d (proven %v), want %d", test.name, sol.Value, proven, test.optimum)
+		}
+		if _, err := Verify(inst, sol.Tour, inst.depot(), sol.Value); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+	}
+}
+
+func TestSolveExactLimits(t *testing.T) {
+	inst := bundledInstance(t, "DEGREE", "D1NoRPP")
+	sol, proven, err := SolveExact(inst, ExactLimits{Nodes: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if proven || !sol.Stopped {
+		t.Error("search with a node limit not stopped")
+	}
+	if _, err := Verify(inst, sol.Tour, inst.depot(), sol.Value); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCutBound(t *testing.T) {
+	// The component of 3 and 4, with a loop at 4, is worth 19 + 2 but
+	// reaching it from the depot's, worth 4, crosses 2-3 at a loss of 10.
+	inst := parseTestInstance(t, `number of vertices : 4
+number of required edges 3
+1 2 1 5
+3 4 1 20
+4 4 1 3
+number of non required edges 2
+2 3 10 0
+1 4 30 0
+`)
+	links, loops := boundLinks(inst)
+	if len(links) != 4 || len(loops) != 1 {
+		t.Fatalf("links %v and loops %v", links, loops)
+	}
+	gain := loopGains(inst)
+	if gain[3] != 2 {
+		t.Errorf("loop gains %v", gain)
+	}
+	// links are 1-2, 3-4, 2-3 and 1-4.
+	for _, test := range []struct {
+		name  string
+		state []int
+		bound int
+	}{
+		{"undecided", []int{0, 0, 0, 0}, 15},
+		{"without 3-4", []int{0, -1, 0, 0}, 4},
+		{"without 2-3", []int{0, 0, -1, 0}, 4},
+		{"with 1-2 and 2-3", []int{1, 0, 1, 0}, 21},
+		{"with everything", []int{1, 1, 1, 0}, 0},
+	} {
+		if bound := cutBound(inst, links, test.state, gain); bound != test.bound {
+			t.Errorf("%s: bound %d, want %d", test.name, bound, test.bound)
+		}
+	}
+	if bound := UpperBound(inst); bound != 15 {
+		t.Errorf("UpperBound %d, want 15", bound)
+	}
+	if bound := TrivialBound(inst); bound != 25 {
+		t.Errorf("TrivialBound %d, want 25", bound)
+	}
+}
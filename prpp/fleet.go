@@ -0,0 +1,211 @@
+package prpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// FleetOptions sets the vehicles of SolveFleet.
+type FleetOptions struct {
+	Vehicles int // routes to build, 1 when zero
+	Budget   int // maximum cost of each route, no limit when zero
+}
+
+// FleetSolution holds one closed walk from the depot per vehicle. Its
+// embedded Evaluation is the combined objective given by EvaluateFleet.
+// UpperBound is the bound of a single walk, which no fleet can beat since
+// its routes could be walked one after the other. Dropped counts the
+// profitable edges that no route serves.
+type FleetSolution struct {
+	Routes     []*Solution
+	UpperBound int
+	Dropped    int
+	Stopped    bool
+	Evaluation
+}
+
+// SolveFleet builds the routes of several vehicles that leave the depot
+// and come back to it, each costing at most the budget. Vehicle after
+// vehicle, the pipeline of Solve runs on the instance without the benefit
+// of the edges served by the previous routes, nor of those whose round
+// trip from the depot does not fit in the budget. When the walk found
+// costs more than the budget, the vehicle keeps the stretch of it worth
+// the most that fits with the shortest paths from and back to the depot,
+// as fitBudget does, and Improve trims it. Without a budget the first vehicle serves
+// everything and the rest stay at the depot. Last, a route that adds
+// nothing to the others is left at the depot. Once ctx is done, or
+// opts.TimeLimit runs out, the routes not built yet stay at the depot and
+// Stopped is set. opts.Progress is not called.
+func SolveFleet(ctx context.Context, inst *Instance, opts Options, fleet FleetOptions) (*FleetSolution, error) {
+	vehicles := fleet.Vehicles
+	if vehicles < 1 {
+		vehicles = 1
+	}
+	if fleet.Budget < 0 {
+		return nil, errors.New("negative route budget")
+	}
+	// The time limit bounds the whole fleet, not each vehicle.
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+		opts.TimeLimit = 0
+	}
+	opts.Progress = nil
+	fs := &FleetSolution{UpperBound: UpperBound(inst), Routes: make([]*Solution, vehicles)}
+	g, _ := instanceGraph(inst)
+	paths := NewShortestPaths(g)
+
+	own := &Instance{Name: inst.Name, Vertices: inst.Vertices, Links: append([]Link{}, inst.Links...), Depot: inst.Depot}
+	if fleet.Budget > 0 {
+		// No route can serve a link whose round trip from the depot
+		// costs more than the budget.
+		depot := inst.depot() - 1
+		for k, link := range own.Links {
+			trip := paths.Dist(depot, link.From-1) + link.Cost + paths.Dist(link.To-1, depot)
+			if !link.OneWay {
+				trip = min(trip, paths.Dist(depot, link.To-1)+link.Back+paths.Dist(link.From-1, depot))
+			}
+			if trip > fleet.Budget {
+				own.Links[k].Benefit = 0
+			}
+		}
+	}
+	walks := make([][]int, vehicles)
+	for v := range fs.Routes {
+		fs.Routes[v] = &Solution{Tour: []int{inst.depot()}}
+		walks[v] = fs.Routes[v].Tour
+	}
+	for v := range fs.Routes {
+		if fs.Stopped {
+			break
+		}
+		sol, err := SolveContext(ctx, own, opts)
+		if err != nil {
+			return nil, err
+		}
+		fs.Stopped = sol.Stopped
+		walk := sol.Tour
+		if fleet.Budget > 0 && sol.Cost > fleet.Budget {
+			walk, err = fitBudget(own, walk, paths, fleet.Budget)
+			if err != nil {
+				return nil, err
+			}
+			if opts.Improve {
+				if walk, _, err = Improve(own, walk); err != nil {
+					return nil, fmt.Errorf("route is not a walk of the instance: %v", err)
+				}
+			}
+		}
+		eval, err := Evaluate(own, walk)
+		if err != nil {
+			return nil, fmt.Errorf("route is not a walk of the instance: %v", err)
+		}
+		if eval.Value <= 0 {
+			// Nothing left is worth a trip within budget.
+			break
+		}
+		fs.Routes[v] = &Solution{Tour: walk, Evaluation: *eval, Diagnostics: sol.Diagnostics}
+		walks[v] = walk
+		// The next vehicles do not collect again what this one did.
+		for _, t := range eval.Edges {
+			own.Links[t.Index].Benefit = 0
+		}
+	}
+	for v := range fs.Routes {
+		if len(walks[v]) > 1 {
+			eval, err := Evaluate(inst, walks[v])
+			if err != nil {
+				return nil, fmt.Errorf("route is not a walk of the instance: %v", err)
+			}
+			fs.Routes[v].Evaluation = *eval
+		}
+	}
+	// A later route may serve most of what an earlier one does; send home
+	// the routes that add nothing to the others.
+	eval, err := EvaluateFleet(inst, walks)
+	if err != nil {
+		return nil, fmt.Errorf("route is not a walk of the instance: %v", err)
+	}
+	for v := range fs.Routes {
+		if len(walks[v]) == 1 {
+			continue
+		}
+		kept := walks[v]
+		walks[v] = []int{inst.depot()}
+		if without, err := EvaluateFleet(inst, walks); err == nil && without.Value > eval.Value {
+			eval = without
+			fs.Routes[v] = &Solution{Tour: walks[v]}
+			continue
+		}
+		walks[v] = kept
+	}
+	fs.Evaluation = *eval
+
+	served := make(map[int]bool, len(eval.Edges))
+	for _, t := range eval.Edges {
+		served[t.Index] = true
+	}
+	for k, link := range inst.Links {
+		if !served[k] && link.Benefit > 0 && link.Benefit-link.minCost() >= 0 {
+			fs.Dropped++
+		}
+	}
+	return fs, nil
+}
+
+// fitBudget cuts the closed walk tour down to a closed walk from its first
+// vertex that costs at most budget: a stretch tour[s..e] of the tour, with
+// the shortest path to its start and from its end. Every stretch that fits
+// is weighed by the benefit it collects less its cost, leaving out what
+// the paths may collect on the way, and the best one is returned; starts
+// and ends of the tour are the stretches without a path on one side.
+func fitBudget(inst *Instance, tour []int, paths *ShortestPaths, budget int) ([]int, error) {
+	steps, err := walkLinks(inst, tour)
+	if err != nil {
+		return nil, err
+	}
+	depot := tour[0] - 1
+	best, bestValue := []int{tour[0]}, 0
+	bestStart, bestEnd := -1, -1
+	walked := make([]bool, len(inst.Links))
+	for s := range steps {
+		there := paths.Dist(depot, tour[s]-1)
+		if there == math.MaxInt32 {
+			continue
+		}
+		for k := range walked {
+			walked[k] = false
+		}
+		benefit, cost := 0, there
+		for e := s; e < len(steps); e++ {
+			k := steps[e]
+			cost += inst.Links[k].stepCost(tour[e])
+			if cost > budget {
+				break
+			}
+			if !walked[k] {
+				walked[k] = true
+				benefit += inst.Links[k].Benefit
+			}
+			back := paths.Dist(tour[e+1]-1, depot)
+			if back != math.MaxInt32 && cost+back <= budget && benefit-cost-back > bestValue {
+				bestValue, bestStart, bestEnd = benefit-cost-back, s, e+1
+			}
+		}
+	}
+	if bestStart < 0 {
+		return best, nil
+	}
+	walk := []int{tour[0]}
+	for _, w := range paths.Path(depot, tour[bestStart]-1) {
+		walk = append(walk, w+1)
+	}
+	walk = append(walk, tour[bestStart+1:bestEnd+1]...)
+	for _, w := range paths.Path(tour[bestEnd]-1, depot) {
+		walk = append(walk, w+1)
+	}
+	return walk, nil
+}
@@ -0,0 +1,48 @@
+package prpp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestFitBudgetMiddleStretch(t *testing.T) {
+	// Only the stretch 1 3 1 in the middle of the tour fits in the budget.
+	inst := parseTestInstance(t, `number of vertices : 3
+number of required edges 1
+1 3 1 10
+number of non required edges 1
+1 2 100 0
+`)
+	g, _ := instanceGraph(inst)
+	walk, err := fitBudget(inst, []int{1, 2, 1, 3, 1, 2, 1}, NewShortestPaths(g), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 3, 1}; !reflect.DeepEqual(walk, want) {
+		t.Errorf("walk %v, want %v", walk, want)
+	}
+}
+
+func TestSolveFleetBudget(t *testing.T) {
+	inst := bundledInstance(t, "DEGREE", "D5NoRPP")
+	for _, budget := range []int{100, 200, 400} {
+		fs, err := SolveFleet(context.Background(), inst, DefaultOptions(), FleetOptions{Vehicles: 3, Budget: budget})
+		if err != nil {
+			t.Fatal(err)
+		}
+		walks := [][]int{}
+		for v, route := range fs.Routes {
+			if route.Cost > budget {
+				t.Errorf("budget %d: route %d costs %d", budget, v+1, route.Cost)
+			}
+			walks = append(walks, route.Tour)
+		}
+		if _, err := VerifyFleet(inst, walks, inst.depot(), fs.Value); err != nil {
+			t.Errorf("budget %d: %v", budget, err)
+		}
+		if fs.Value > fs.UpperBound {
+			t.Errorf("budget %d: value %d above the bound %d", budget, fs.Value, fs.UpperBound)
+		}
+	}
+}
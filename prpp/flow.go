@@ -0,0 +1,129 @@
+package prpp
+
+import "math"
+
+// flowNetwork finds minimum cost flows by successive shortest paths, with
+// Bellman-Ford on the residual network since reversed arcs cost less than
+// zero. The networks built by balanceDegrees are small, one node per
+// unbalanced vertex.
+type flowNetwork struct {
+	arcs []flowArc
+	out  [][]int // indices in arcs of the arcs leaving each node
+}
+
+// flowArc is an arc of the residual network; arc i^1 is its reverse.
+type flowArc struct {
+	to       int
+	capacity int
+	cost     int
+}
+
+func newFlowNetwork(nodes int) *flowNetwork {
+	return &flowNetwork{out: make([][]int, nodes)}
+}
+
+// addArc adds an arc and returns its index, to read its flow afterwards.
+func (f *flowNetwork) addArc(from, to, capacity, cost int) int {
+	i := len(f.arcs)
+	f.arcs = append(f.arcs, flowArc{to, capacity, cost}, flowArc{from, 0, -cost})
+	f.out[from] = append(f.out[from], i)
+	f.out[to] = append(f.out[to], i+1)
+	return i
+}
+
+// flow is the flow sent along arc i.
+func (f *flowNetwork) flow(i int) int {
+	return f.arcs[i^1].capacity
+}
+
+// minCostFlow sends as much flow as possible from source to sink at the
+// least cost and returns the flow sent and its cost.
+func (f *flowNetwork) minCostFlow(source, sink int) (flow, cost int) {
+	n := len(f.out)
+	for {
+		dist := make([]int, n)
+		via := make([]int, n)
+		for i := range dist {
+			dist[i] = math.MaxInt32
+			via[i] = -1
+		}
+		dist[source] = 0
+		for round, changed := 0, true; changed && round < n; round++ {
+			changed = false
+			for u := 0; u < n; u++ {
+				if dist[u] == math.MaxInt32 {
+					continue
+				}
+				for _, i := range f.out[u] {
+					a := f.arcs[i]
+					if a.capacity > 0 && dist[u]+a.cost < dist[a.to] {
+						dist[a.to] = dist[u] + a.cost
+						via[a.to] = i
+						changed = true
+					}
+				}
+			}
+		}
+		if dist[sink] == math.MaxInt32 {
+			return flow, cost
+		}
+
+		push := math.MaxInt32
+		for v := sink; v != source; v = f.arcs[via[v]^1].to {
+			push = min(push, f.arcs[via[v]].capacity)
+		}
+		for v := sink; v != source; v = f.arcs[via[v]^1].to {
+			f.arcs[via[v]].capacity -= push
+			f.arcs[via[v]^1].capacity += push
+		}
+		flow += push
+		cost += push * dist[sink]
+	}
+}
+
+// transfer is a number of copies of the shortest path from one vertex to
+// another, 0-based, added to balance a directed graph.
+type transfer struct {
+	from, to, copies int
+}
+
+// balanceDegrees finds the cheapest way to balance a directed graph with
+// shortest paths. surplus[v] is how many more arcs enter v than leave it,
+// so paths must start at the vertices where it is positive and end where
+// it is negative; dist gives the cost of a shortest path. Pairs without a
+// path are left out, so the returned transfers fall short of balancing the
+// graph when it is not strongly connected.
+func balanceDegrees(surplus []int, dist func(u, v int) int) []transfer {
+	var sources, sinks []int
+	for v, s := range surplus {
+		if s > 0 {
+			sources = append(sources, v)
+		} else if s < 0 {
+			sinks = append(sinks, v)
+		}
+	}
+	f := newFlowNetwork(len(sources) + len(sinks) + 2)
+	source, sink := len(sources)+len(sinks), len(sources)+len(sinks)+1
+	type pair struct{ arc, from, to int }
+	pairs := []pair{}
+	for i, u := range sources {
+		f.addArc(source, i, surplus[u], 0)
+		for j, v := range sinks {
+			if d := dist(u, v); d != math.MaxInt32 {
+				pairs = append(pairs, pair{f.addArc(i, len(sources)+j, math.MaxInt32, d), u, v})
+			}
+		}
+	}
+	for j, v := range sinks {
+		f.addArc(len(sources)+j, sink, -surplus[v], 0)
+	}
+	f.minCostFlow(source, sink)
+
+	transfers := []transfer{}
+	for _, p := range pairs {
+		if copies := f.flow(p.arc); copies > 0 {
+			transfers = append(transfers, transfer{p.from, p.to, copies})
+		}
+	}
+	return transfers
+}
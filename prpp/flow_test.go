@@ -0,0 +1,116 @@
+package prpp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMinCostFlow(t *testing.T) {
+	// Two units from 0 to 3: the cheap route 0 1 3 only takes one, and
+	// sending the second over 0 2 1 3 would need the reverse of 1 3.
+	f := newFlowNetwork(4)
+	a := f.addArc(0, 1, 1, 1)
+	b := f.addArc(0, 2, 2, 2)
+	c := f.addArc(1, 3, 2, 1)
+	d := f.addArc(2, 3, 1, 5)
+	e := f.addArc(2, 1, 1, 1)
+	flow, cost := f.minCostFlow(0, 3)
+	if flow != 3 || cost != 2+4+7 {
+		t.Errorf("flow %d at cost %d, want 3 at 13", flow, cost)
+	}
+	want := []int{1, 2, 2, 1, 1}
+	for i, arc := range []int{a, b, c, d, e} {
+		if f.flow(arc) != want[i] {
+			t.Errorf("arc %d carries %d, want %d", i, f.flow(arc), want[i])
+		}
+	}
+}
+
+func TestBalanceDegrees(t *testing.T) {
+	// Vertices 0 and 1 have one arc in too many, 2 and 3 one out.
+	dist := [][]int{
+		{0, 1, 4, 1},
+		{1, 0, 1, 9},
+		{4, 1, 0, 1},
+		{1, 9, 1, 0},
+	}
+	for _, test := range []struct {
+		name    string
+		surplus []int
+		want    []transfer
+	}{
+		{"pairs", []int{1, 1, -1, -1}, []transfer{{0, 3, 1}, {1, 2, 1}}},
+		{"copies", []int{2, 0, -1, -1}, []transfer{{0, 2, 1}, {0, 3, 1}}},
+		{"balanced", []int{0, 0, 0, 0}, []transfer{}},
+	} {
+		transfers := balanceDegrees(test.surplus, func(u, v int) int { return dist[u][v] })
+		if !reflect.DeepEqual(transfers, test.want) {
+			t.Errorf("%s: %v, want %v", test.name, transfers, test.want)
+		}
+	}
+
+	// Without a path from 0 to 2 the surplus is left.
+	transfers := balanceDegrees([]int{1, 0, -1}, func(u, v int) int { return math.MaxInt32 })
+	if len(transfers) != 0 {
+		t.Errorf("unreachable: %v", transfers)
+	}
+}
+
+func TestSolveDirected(t *testing.T) {
+	inst := parseTestInstance(t, `number of vertices : 4
+number of required edges 3
+1 2 3 10 7
+2 3 2 12 -
+3 4 1 8 20
+number of non required edges 3
+4 1 5 0 1
+3 1 9 0 -
+1 3 4 0 -
+`)
+	for _, opts := range []Options{{}, DefaultOptions(), {GRASP: true, Iterations: 5, Seed: 1, Alpha: 0.5}} {
+		sol, err := Solve(inst, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Verify(inst, sol.Tour, 1, sol.Value); err != nil {
+			t.Errorf("%+v: tour %v: %v", opts, sol.Tour, err)
+		}
+		// 1 2 3 4 1 serves everything at 3 + 2 + 1 + 5.
+		if opts.Improve && sol.Value != 19 {
+			t.Errorf("%+v: value %d, want 19", opts, sol.Value)
+		}
+	}
+}
+
+func TestSolveDirectedUnreachable(t *testing.T) {
+	// No closed walk from the depot takes the arcs 1 2 and 2 3, nor the
+	// pair 3 4 and 4 3 that nothing joins to the depot.
+	for _, test := range []struct{ name, text string }{
+		{"one way", `number of vertices : 3
+number of required edges 2
+1 2 1 10 -
+2 3 1 10 -
+number of non required edges 0
+`},
+		{"apart", `number of vertices : 4
+number of required edges 2
+3 4 1 10 -
+4 3 1 10 -
+number of non required edges 1
+1 2 1 0 -
+`},
+	} {
+		inst := parseTestInstance(t, test.text)
+		for _, opts := range []Options{{}, {Prune: true, PruneRepeat: true, Improve: true}, {GRASP: true, Iterations: 5, Seed: 1}} {
+			sol, err := Solve(inst, opts)
+			if err != nil {
+				t.Errorf("%s %+v: %v", test.name, opts, err)
+				continue
+			}
+			if len(sol.Tour) != 1 || sol.Tour[0] != 1 || sol.Value != 0 {
+				t.Errorf("%s %+v: tour %v value %d, want [1] 0", test.name, opts, sol.Tour, sol.Value)
+			}
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package prpp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Distribution draws the costs or benefits of generated edges. Kind is
+// "uniform", integers between A and B; "normal", mean A and standard
+// deviation B, rounded; or "const", always A. Negative draws become 0.
+type Distribution struct {
+	Kind string
+	A, B float64
+}
+
+// ParseDistribution reads a distribution written as "uniform:1:100",
+// "normal:50:10" or "const:1".
+func ParseDistribution(s string) (Distribution, error) {
+	fields := strings.Split(s, ":")
+	d := Distribution{Kind: fields[0]}
+	want := 3
+	if d.Kind == "const" {
+		want = 2
+	} else if d.Kind != "uniform" && d.Kind != "normal" {
+		return d, fmt.Errorf("unknown distribution %q", fields[0])
+	}
+	if len(fields) != want {
+		return d, fmt.Errorf("distribution %q needs %d parameters", s, want-1)
+	}
+	params := []*float64{&d.A, &d.B}
+	for i, field := range fields[1:] {
+		value, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return d, fmt.Errorf("invalid parameter %q in distribution %q", field, s)
+		}
+		*params[i] = value
+	}
+	if d.Kind == "uniform" && d.B < d.A {
+		return d, fmt.Errorf("empty range in distribution %q", s)
+	}
+	return d, nil
+}
+
+func (d Distribution) String() string {
+	if d.Kind == "const" {
+		return fmt.Sprintf("const:%g", d.A)
+	}
+	return fmt.Sprintf("%s:%g:%g", d.Kind, d.A, d.B)
+}
+
+func (d Distribution) draw(rng *rand.Rand) int {
+	var value float64
+	switch d.Kind {
+	case "uniform":
+		low, high := math.Ceil(d.A), math.Floor(d.B)
+		value = low
+		if high > low {
+			value += float64(rng.Int63n(int64(high-low) + 1))
+		}
+	case "normal":
+		value = math.Floor(d.A + d.B*rng.NormFloat64() + 0.5)
+	default:
+		value = math.Floor(d.A + 0.5)
+	}
+	if value < 0 {
+		return 0
+	}
+	return int(value)
+}
+
+// Generator kinds, after the bundled families they imitate.
+const (
+	GenRandom = "random" // Vertices vertices, Density of all the pairs joined
+	GenGrid   = "grid"   // Rows x Columns grid, each vertex joined to its neighbors
+	GenDegree = "degree" // like random, no vertex with more than MaxDegree edges
+)
+
+// GenOptions describes the instances built by Generate.
+type GenOptions struct {
+	Kind      string
+	Name      string
+	Vertices  int     // random and degree
+	Density   float64 // random and degree, fraction of the n(n-1)/2 pairs
+	Rows      int     // grid
+	Columns   int     // grid
+	MaxDegree int     // degree
+
+	// Required is the fraction of the edges marked required. Their
+	// benefits are drawn from RequiredBenefit and those of the other
+	// edges from Benefit; the costs of all of them from Cost.
+	Required        float64
+	Cost            Distribution
+	Benefit         Distribution
+	RequiredBenefit Distribution
+
+	// Connected makes every vertex reachable from every other one.
+	Connected bool
+	// Instances built with the same options and Seed are the same.
+	Seed int64
+}
+
+// Generate builds a random instance. Its edges join distinct vertices and
+// no pair twice; the required ones come first, as in the NoRPP files.
+func Generate(opts GenOptions) (*Instance, error) {
+	rng := rand.New(rand.NewSource(opts.Seed))
+	var pairs [][2]int
+	n := opts.Vertices
+	switch opts.Kind {
+	case GenRandom, GenDegree:
+		if n < 1 {
+			return nil, errors.New("instance needs at least one vertex")
+		}
+		if opts.Density < 0 || opts.Density > 1 {
+			return nil, fmt.Errorf("density %g out of range 0..1", opts.Density)
+		}
+		maxDegree := n
+		if opts.Kind == GenDegree {
+			maxDegree = opts.MaxDegree
+			if maxDegree < 1 || (opts.Connected && n > 2 && maxDegree < 2) {
+				return nil, fmt.Errorf("maximum degree %d too small", maxDegree)
+			}
+		}
+		pairs = randomPairs(n, int(opts.Density*float64(n*(n-1)/2)+0.5), maxDegree, opts.Connected, rng)
+	case GenGrid:
+		if opts.Rows < 1 || opts.Columns < 1 {
+			return nil, errors.New("grid needs at least one row and one column")
+		}
+		n = opts.Rows * opts.Columns
+		for r := 0; r < opts.Rows; r++ {
+			for c := 0; c < opts.Columns; c++ {
+				v := r*opts.Columns + c + 1
+				if c+1 < opts.Columns {
+					pairs = append(pairs, [2]int{v, v + 1})
+				}
+				if r+1 < opts.Rows {
+					pairs = append(pairs, [2]int{v, v + opts.Columns})
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unknown instance kind %q", opts.Kind)
+	}
+	if opts.Required < 0 || opts.Required > 1 {
+		return nil, fmt.Errorf("required fraction %g out of range 0..1", opts.Required)
+	}
+
+	required := make([]bool, len(pairs))
+	for _, k := range rng.Perm(len(pairs))[:int(opts.Required*float64(len(pairs))+0.5)] {
+		required[k] = true
+	}
+	inst := &Instance{Name: opts.Name, Vertices: n}
+	for _, wanted := range []bool{true, false} {
+		for k, pair := range pairs {
+			if required[k] != wanted {
+				continue
+			}
+			link := Link{From: pair[0], To: pair[1], Cost: opts.Cost.draw(rng), Required: wanted}
+			link.Back = link.Cost
+			if wanted {
+				link.Benefit = opts.RequiredBenefit.draw(rng)
+			} else {
+				link.Benefit = opts.Benefit.draw(rng)
+			}
+			inst.Links = append(inst.Links, link)
+		}
+	}
+	return inst, nil
+}
+
+// randomPairs joins about edges random pairs of the vertices 1..n, sorted,
+// keeping every degree at most maxDegree. When connected it first joins
+// every vertex to an earlier one, so the edges may exceed the count asked.
+func randomPairs(n, edges, maxDegree int, connected bool, rng *rand.Rand) [][2]int {
+	degree := make([]int, n+1)
+	joined := make(map[[2]int]bool)
+	pairs := [][2]int{}
+	join := func(u, v int) {
+		key := pairKey(u, v)
+		joined[key] = true
+		degree[u]++
+		degree[v]++
+		pairs = append(pairs, key)
+	}
+
+	if connected {
+		order := rng.Perm(n)
+		for i := 1; i < n; i++ {
+			// Join to a random earlier vertex with room for one more edge;
+			// with maxDegree >= 2 the last one joined always has room.
+			candidates := []int{}
+			for _, j := range order[:i] {
+				if degree[j+1] < maxDegree {
+					candidates = append(candidates, j+1)
+				}
+			}
+			join(candidates[rng.Intn(len(candidates))], order[i]+1)
+		}
+	}
+	all := make([][2]int, 0, n*(n-1)/2)
+	for u := 1; u <= n; u++ {
+		for v := u + 1; v <= n; v++ {
+			all = append(all, [2]int{u, v})
+		}
+	}
+	for _, k := range rng.Perm(len(all)) {
+		if len(pairs) >= edges {
+			break
+		}
+		u, v := all[k][0], all[k][1]
+		if !joined[all[k]] && degree[u] < maxDegree && degree[v] < maxDegree {
+			join(u, v)
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || (pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1])
+	})
+	return pairs
+}
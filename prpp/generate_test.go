@@ -0,0 +1,114 @@
+package prpp
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func genOptions(kind string, seed int64) GenOptions {
+	return GenOptions{
+		Kind: kind, Name: "gen", Vertices: 30, Density: 0.2, Rows: 4, Columns: 5, MaxDegree: 3,
+		Required: 0.4, Cost: Distribution{"uniform", 1, 20}, Benefit: Distribution{"const", 0, 0},
+		RequiredBenefit: Distribution{"normal", 30, 10}, Connected: true, Seed: seed,
+	}
+}
+
+func TestGenerateSeed(t *testing.T) {
+	for _, kind := range []string{GenRandom, GenGrid, GenDegree} {
+		first, err := Generate(genOptions(kind, 7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		again, err := Generate(genOptions(kind, 7))
+		if err != nil {
+			t.Fatal(err)
+		}
+		other, err := Generate(genOptions(kind, 8))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(first, again) {
+			t.Errorf("%s: seed 7 gave two instances", kind)
+		}
+		if reflect.DeepEqual(first, other) {
+			t.Errorf("%s: seeds 7 and 8 gave the same instance", kind)
+		}
+	}
+}
+
+func TestGenerateShape(t *testing.T) {
+	for _, kind := range []string{GenRandom, GenGrid, GenDegree} {
+		for seed := int64(1); seed <= 20; seed++ {
+			opts := genOptions(kind, seed)
+			inst, err := Generate(opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sets := newDisjointSets(inst.Vertices)
+			degree := make([]int, inst.Vertices+1)
+			seen := make(map[[2]int]bool)
+			required := true
+			for _, link := range inst.Links {
+				pair := pairKey(link.From, link.To)
+				if link.From == link.To || seen[pair] || link.From < 1 || link.To > inst.Vertices {
+					t.Errorf("%s seed %d: link %d-%d", kind, seed, link.From, link.To)
+				}
+				seen[pair] = true
+				if link.Required && !required {
+					t.Errorf("%s seed %d: required link %d-%d after the others", kind, seed, link.From, link.To)
+				}
+				required = link.Required
+				degree[link.From]++
+				degree[link.To]++
+				sets.union(link.From-1, link.To-1)
+			}
+			for v := 1; v <= inst.Vertices; v++ {
+				if kind == GenDegree && degree[v] > opts.MaxDegree {
+					t.Errorf("%s seed %d: vertex %d has degree %d", kind, seed, v, degree[v])
+				}
+				if sets.find(v-1) != sets.find(0) {
+					t.Errorf("%s seed %d: vertex %d not connected to 1", kind, seed, v)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestRandomPairs(t *testing.T) {
+	for seed := int64(1); seed <= 50; seed++ {
+		for _, test := range []struct {
+			n, edges, maxDegree int
+			connected           bool
+		}{
+			{10, 45, 10, false},
+			{10, 20, 2, true},
+			{12, 5, 3, true},
+			{12, 30, 3, false},
+		} {
+			pairs := randomPairs(test.n, test.edges, test.maxDegree, test.connected, rand.New(rand.NewSource(seed)))
+			sets := newDisjointSets(test.n + 1)
+			degree := make([]int, test.n+1)
+			for i, p := range pairs {
+				if p[0] >= p[1] || (i > 0 && !(pairs[i-1][0] < p[0] || pairs[i-1][0] == p[0] && pairs[i-1][1] < p[1])) {
+					t.Fatalf("%+v seed %d: pairs %v not sorted and distinct", test, seed, pairs)
+				}
+				degree[p[0]]++
+				degree[p[1]]++
+				sets.union(p[0], p[1])
+			}
+			if !test.connected && len(pairs) > test.edges {
+				t.Errorf("%+v seed %d: %d pairs", test, seed, len(pairs))
+			}
+			for v := 1; v <= test.n; v++ {
+				if degree[v] > test.maxDegree {
+					t.Errorf("%+v seed %d: vertex %d has degree %d", test, seed, v, degree[v])
+				}
+				if test.connected && sets.find(v) != sets.find(1) {
+					t.Errorf("%+v seed %d: vertex %d apart", test, seed, v)
+				}
+			}
+		}
+	}
+}
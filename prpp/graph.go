@@ -0,0 +1,796 @@
+//Este codigo es una modificacion del archivo graph.go, proveniente del paquete
+//"github.com/twmb/algoimpl/go/graph" creado por twmb.
+//Solo se modifico la definicion de nodos y lados para contener el costo y
+//beneficio, propiedades necesarias para la realizacion de este proyecto
+
+// Implements an adjacency list graph as a slice of generic nodes
+// and includes some useful graph functions.
+
+package prpp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	dequeued = ^(1<<31 - 1)
+	unseen   = 0
+	seen     = 1
+)
+
+// Graph is an adjacency slice representation of a graph. Can be directed or undirected.
+// It is a multigraph: every edge or arc gets its own id, numbered from 0
+// in order of creation, and parallel edges are kept apart.
+type Graph struct {
+	nodes []*node
+	ends  [][2]*node // end nodes of each edge id, nil once removed
+}
+
+type node struct {
+	edges         []edge
+	reversedEdges []edge
+	index         int
+	state         int   // used for metadata
+	incidence     int   // used for incidence
+	data          int   // also used for metadata
+	parent        *node // also used for metadata
+	container     Node  // who holds me
+}
+
+// Node connects to a backing node on the graph. It can safely be used in maps.
+type Node struct {
+	// In an effort to prevent access to the actual graph
+	// and so that the Node type can be used in a map while
+	// the graph changes metadata, the Node type encapsulates
+	// a pointer to the actual node data.
+	node *node
+	// Value can be used to store information on the caller side.
+	// Its use is optional. See the Topological Sort example for
+	// a reason on why to use this pointer.
+	// The reason it is a pointer is so that graph function calls
+	// can test for equality on Nodes. The pointer wont change,
+	// the value it points to will. If the pointer is explicitly changed,
+	// graph functions that use Nodes will cease to work.
+	Value *interface{}
+}
+
+type edge struct {
+	id      int
+	cost    int
+	benefit int
+	state   int
+	end     *node
+}
+
+// An Edge connects two Nodes in a graph. To modify Weight, use
+// the MakeEdgeWeight function. Any local modifications will
+// not be seen in the graph.
+type Edge struct {
+	Cost    int
+	Benefit int
+	Start   Node
+	End     Node
+}
+
+type Edges []Edge
+
+func (slice Edges) Len() int {
+	return len(slice)
+}
+
+func (slice Edges) Less(i, j int) bool {
+	return (float64(slice[i].Benefit) / float64(slice[i].Cost)) < (float64(slice[j].Benefit) / float64(slice[j].Cost))
+}
+
+func (slice Edges) Swap(i, j int) {
+	slice[i], slice[j] = slice[j], slice[i]
+}
+
+// To String Function
+func (n Node) String() string {
+	return fmt.Sprintf("%d", *n.Value)
+}
+
+func (n *node) String() string {
+	ed := fmt.Sprintf("%d -> [", *n.container.Value)
+	for _, edge := range n.edges {
+		ed = ed + fmt.Sprintf("(%d,%d)", *n.container.Value, *edge.end.container.Value)
+	}
+	ed = ed + "]"
+	return ed
+}
+
+func (g Graph) String() string {
+	nodes := ""
+	for _, node := range g.nodes {
+		nodes = nodes + node.String() + "\n"
+	}
+	return nodes
+}
+
+// NewGraph creates and returns an empty graph.
+// This function returns an undirected graph by default.
+func NewGraph() *Graph {
+	g := &Graph{}
+	return g
+}
+
+// MakeNode creates a node, adds it to the graph and returns the new node.
+func (g *Graph) MakeNode() Node {
+	newNode := &node{index: len(g.nodes), incidence: 0}
+	newNode.container = Node{node: newNode, Value: new(interface{})}
+	g.nodes = append(g.nodes, newNode)
+	return newNode.container
+}
+
+// RemoveNode removes a node from the graph and all edges connected to it,
+// parallel edges and loops included, going through their ids as
+// RemoveEdgeID does. This function nils points in the Node structure. If
+// 'remove' is used in a map, you must delete the map index first.
+func (g *Graph) RemoveNode(remove *Node) {
+	if remove.node == nil {
+		return
+	}
+	n := remove.node
+	if n.index < len(g.nodes) && g.nodes[n.index] == n {
+		for len(n.edges) > 0 {
+			g.RemoveEdgeID(n.edges[0].id)
+		}
+		for len(n.reversedEdges) > 0 {
+			g.RemoveEdgeID(n.reversedEdges[0].id)
+		}
+		// O(V)
+		for _, other := range g.nodes[n.index+1:] {
+			other.index--
+		}
+		copy(g.nodes[n.index:], g.nodes[n.index+1:])
+		g.nodes = g.nodes[:len(g.nodes)-1]
+	}
+	n.parent = nil
+	remove.node = nil
+}
+
+// MakeEdge creates  an edge in the graph with a corresponding cost and
+// returns its id. It returns an error if either of the nodes do not belong
+// in the graph.
+//
+// Calling MakeEdge multiple times on the same nodes creates parallel edges,
+// each with its own id.
+func (g *Graph) MakeEdge(from, to Node, cost, benefit int) (int, error) {
+	// fmt.Println(from.node)
+	if from.node == nil || from.node.index >= len(g.nodes) || g.nodes[from.node.index] != from.node {
+		return -1, errors.New("First node in MakeEdge call does not belong to this graph")
+	}
+	if to.node == nil || to.node.index >= len(g.nodes) || g.nodes[to.node.index] != to.node {
+		return -1, errors.New("Second node in MakeEdge call does not belong to this graph")
+	}
+
+	id := len(g.ends)
+	g.ends = append(g.ends, [2]*node{from.node, to.node})
+	newEdge := edge{id: id, cost: cost, benefit: benefit, end: to.node}
+	from.node.edges = append(from.node.edges, newEdge)
+	reversedEdge := edge{id: id, cost: cost, benefit: benefit, end: from.node} // cost for undirected graph only
+	if to != from {
+		to.node.edges = append(to.node.edges, reversedEdge)
+	}
+	return id, nil
+}
+
+// MakeArc creates an arc that can only be walked from the from node to the
+// to node and returns its id. The to node keeps it among its reversed
+// edges, so a graph built only with arcs is directed.
+func (g *Graph) MakeArc(from, to Node, cost, benefit int) (int, error) {
+	if from.node == nil || from.node.index >= len(g.nodes) || g.nodes[from.node.index] != from.node {
+		return -1, errors.New("First node in MakeArc call does not belong to this graph")
+	}
+	if to.node == nil || to.node.index >= len(g.nodes) || g.nodes[to.node.index] != to.node {
+		return -1, errors.New("Second node in MakeArc call does not belong to this graph")
+	}
+	id := len(g.ends)
+	g.ends = append(g.ends, [2]*node{from.node, to.node})
+	from.node.edges = append(from.node.edges, edge{id: id, cost: cost, benefit: benefit, end: to.node})
+	to.node.reversedEdges = append(to.node.reversedEdges, edge{id: id, cost: cost, benefit: benefit, end: from.node})
+	return id, nil
+}
+
+// MakeWindyEdge creates an edge that costs cost from the from node to the
+// to node and back the other way, as two arcs, and returns their ids.
+func (g *Graph) MakeWindyEdge(from, to Node, cost, back, benefit int) (forward, backward int, err error) {
+	if forward, err = g.MakeArc(from, to, cost, benefit); err != nil {
+		return -1, -1, err
+	}
+	if backward, err = g.MakeArc(to, from, back, benefit); err != nil {
+		return -1, -1, err
+	}
+	return forward, backward, nil
+}
+
+// EdgeEnds returns the nodes joined by the edge or arc id, an arc going
+// from the first to the second. ok is false if there is no such edge.
+func (g *Graph) EdgeEnds(id int) (from, to Node, ok bool) {
+	if id < 0 || id >= len(g.ends) || g.ends[id][0] == nil {
+		return Node{}, Node{}, false
+	}
+	return g.ends[id][0].container, g.ends[id][1].container, true
+}
+
+// RemoveEdge removes one edge starting at the from node and ending at the to
+// node, the first one made among parallel edges. Both ends lose the same
+// edge, as RemoveEdgeID does.
+func (g *Graph) RemoveEdge(from, to Node) {
+	for _, e := range from.node.edges {
+		if e.end == to.node {
+			g.RemoveEdgeID(e.id)
+			return
+		}
+	}
+}
+
+// RemoveEdgeID removes the edge or arc id from the graph.
+func (g *Graph) RemoveEdgeID(id int) {
+	if id < 0 || id >= len(g.ends) || g.ends[id][0] == nil {
+		return
+	}
+	for _, n := range g.ends[id] {
+		for _, list := range []*[]edge{&n.edges, &n.reversedEdges} {
+			for e := range *list {
+				if (*list)[e].id == id {
+					swapNRemoveEdge(e, list)
+					break
+				}
+			}
+		}
+	}
+	g.ends[id] = [2]*node{}
+}
+
+// Neighbors returns a slice of nodes that are reachable from the given node in a graph.
+func (g *Graph) Neighbors(n Node) []Node {
+	neighbors := make([]Node, 0, len(n.node.edges))
+	if g.nodes[n.node.index] == n.node {
+		for _, edge := range n.node.edges {
+			neighbors = append(neighbors, edge.end.container)
+		}
+	}
+	return neighbors
+}
+
+// Swaps an edge to the end of the edges slice and 'removes' it by reslicing.
+func swapNRemoveEdge(remove int, edges *[]edge) {
+	(*edges)[remove], (*edges)[len(*edges)-1] = (*edges)[len(*edges)-1], (*edges)[remove]
+	*edges = (*edges)[:len(*edges)-1]
+}
+
+func (g *Graph) bfs(n *node, finishList *[]Node) {
+	totalBenefit := 0
+	queue := make([]*node, 0, len(n.edges))
+	queue = append(queue, n)
+	for i := 0; i < len(queue); i++ {
+		node := queue[i]
+		node.state = seen
+		for _, edge := range node.edges {
+			if edge.end.state == unseen {
+				edge.end.state = seen
+				totalBenefit = totalBenefit + edge.benefit - edge.cost
+				queue = append(queue, edge.end)
+			}
+		}
+	}
+	*finishList = make([]Node, 0, len(queue))
+	for i := range queue {
+		*finishList = append(*finishList, queue[i].container)
+	}
+}
+
+func (g *Graph) bfsMap(n *node, finishList map[int]Node) map[int]Node {
+	queue := make([]*node, 0, len(n.edges))
+	queue = append(queue, n)
+	for i := 0; i < len(queue); i++ {
+		node := queue[i]
+		node.state = seen
+		for _, edge := range node.edges {
+			if edge.end.state == unseen {
+				edge.end.state = seen
+				queue = append(queue, edge.end)
+			}
+		}
+	}
+	finishList = make(map[int]Node, len(queue))
+	for i := range queue {
+		finishList[queue[i].index] = queue[i].container
+	}
+	return finishList
+}
+
+func (g *Graph) ConnectedComponentsMap() []map[int]Node {
+	componentMap := make([]map[int]Node, 0)
+	for _, node := range g.nodes {
+		if node.state == unseen {
+			component := make(map[int]Node, 0)
+			component = g.bfsMap(node, component)
+			componentMap = append(componentMap, component)
+		}
+	}
+	return componentMap
+}
+
+// ConnectedComponents algorithm for an undirected graph
+func (g *Graph) ConnectedComponents() [][]Node {
+	components := make([][]Node, 0)
+	for _, node := range g.nodes {
+		if node.state == unseen {
+			component := make([]Node, 0)
+			g.bfs(node, &component)
+			components = append(components, component)
+		}
+	}
+	return components
+}
+
+// ConnectedComponentOfNode returns the connected component of the i
+func (g *Graph) ConnectedComponentOfNode(node *node) []Node {
+	component := make([]Node, 0)
+	g.bfs(node, &component)
+	return component
+}
+
+// LinkComponents adds, in order, each edge of edges that joins two different
+// components, and returns the edges added.
+func (g *Graph) LinkComponents(edges Edges) Edges {
+	added := Edges{}
+	sets := g.components()
+	for _, edge := range edges {
+		if sets.union(edge.Start.node.index, edge.End.node.index) {
+			g.MakeEdge(edge.Start, edge.End, edge.Cost, edge.Benefit)
+			added = append(added, edge)
+		}
+	}
+	return added
+}
+
+// LinkComponentsMST joins the components of g that have edges, and the one
+// of the node with index root even if it has none, over the shortest paths
+// of network, an undirected graph over the same nodes: the paths of the
+// tree of componentTree are added to g over the cheapest edges of network.
+// It returns the node indices of every path added. Components that network
+// cannot reach are left apart.
+func (g *Graph) LinkComponentsMST(network *Graph, root int) [][]int {
+	_, tree := g.componentTree(network, root)
+	added := [][]int{}
+	for _, l := range tree {
+		for k := 1; k < len(l.path); k++ {
+			e := cheapestEdge(network, l.path[k-1], l.path[k])
+			g.MakeEdge(g.nodes[l.path[k-1]].container, g.nodes[l.path[k]].container, e.cost, e.benefit)
+		}
+		added = append(added, l.path)
+	}
+	return added
+}
+
+// cheapestEdge is the cheapest of the edges of g from node from to node to,
+// both 0-based, which a shortest path between them walks.
+func cheapestEdge(g *Graph, from, to int) edge {
+	cheapest := -1
+	for i, e := range g.nodes[from].edges {
+		if e.end.index == to && (cheapest < 0 || e.cost < g.nodes[from].edges[cheapest].cost) {
+			cheapest = i
+		}
+	}
+	return g.nodes[from].edges[cheapest]
+}
+
+// componentLink is an edge of the tree of componentTree: the shortest path,
+// as node indices, from a node of one of the components i and j to the
+// closest node of the other.
+type componentLink struct {
+	dist, i, j int
+	path       []int
+}
+
+// componentTree contracts to a vertex each component of g that has edges,
+// weak components if they are arcs, and the one of the node with index
+// root even if it has none, and returns the node indices of each component
+// with the links of a minimum spanning tree of the contracted graph, found
+// with Kruskal's algorithm. Two components are as far apart as the
+// shortest path in network between their closest nodes, going the cheaper
+// way when network has arcs. Components that network cannot reach are
+// left out of the tree.
+func (g *Graph) componentTree(network *Graph, root int) (members [][]int, tree []componentLink) {
+	sets := g.components()
+	position := make(map[int]int)
+	for v, n := range g.nodes {
+		if len(n.edges) == 0 && len(n.reversedEdges) == 0 && v != root {
+			continue
+		}
+		c := sets.find(v)
+		if _, ok := position[c]; !ok {
+			position[c] = len(members)
+			members = append(members, nil)
+		}
+		members[position[c]] = append(members[position[c]], v)
+	}
+
+	// The closest pair of nodes between components i < j, found from the
+	// shortest path forest of component from, i unless going from j is
+	// cheaper.
+	type link struct{ dist, i, j, from, end int }
+	trees := make([]*pathTree, len(members))
+	for i := range members {
+		trees[i] = network.dijkstraFrom(members[i])
+	}
+	closest := func(from, to int) (int, int) {
+		end := -1
+		for _, v := range members[to] {
+			if d := trees[from].dist[v]; d != math.MaxInt32 && (end < 0 || d < trees[from].dist[end]) {
+				end = v
+			}
+		}
+		if end < 0 {
+			return math.MaxInt32, -1
+		}
+		return trees[from].dist[end], end
+	}
+	links := []link{}
+	for i := range members {
+		for j := i + 1; j < len(members); j++ {
+			forward, end := closest(i, j)
+			if backward, start := closest(j, i); backward < forward {
+				links = append(links, link{backward, i, j, j, start})
+			} else if end >= 0 {
+				links = append(links, link{forward, i, j, i, end})
+			}
+		}
+	}
+	sort.SliceStable(links, func(a, b int) bool {
+		return links[a].dist < links[b].dist
+	})
+
+	joined := newDisjointSets(len(members))
+	for _, l := range links {
+		if !joined.union(l.i, l.j) {
+			continue
+		}
+		prev := trees[l.from].prev
+		path := []int{l.end}
+		for v := l.end; prev[v] >= 0; v = prev[v] {
+			path = append(path, prev[v])
+		}
+		for a, b := 0, len(path)-1; a < b; a, b = a+1, b-1 {
+			path[a], path[b] = path[b], path[a]
+		}
+		tree = append(tree, componentLink{l.dist, l.i, l.j, path})
+	}
+	return members, tree
+}
+
+// reachable marks the nodes that a walk of g from the node with index
+// start reaches, or with backward those from which a walk reaches it,
+// following arcs against their direction.
+func (g *Graph) reachable(start int, backward bool) []bool {
+	marked := make([]bool, len(g.nodes))
+	marked[start] = true
+	queue := []int{start}
+	for len(queue) > 0 {
+		n := g.nodes[queue[0]]
+		queue = queue[1:]
+		edges := n.edges
+		if backward {
+			edges = n.reversedEdges
+		}
+		for _, e := range edges {
+			if !marked[e.end.index] {
+				marked[e.end.index] = true
+				queue = append(queue, e.end.index)
+			}
+		}
+	}
+	return marked
+}
+
+// components returns the connected components of g as disjoint sets of
+// node indices.
+func (g *Graph) components() disjointSets {
+	sets := newDisjointSets(len(g.nodes))
+	for _, n := range g.nodes {
+		for _, edge := range n.edges {
+			sets.union(n.index, edge.end.index)
+		}
+	}
+	return sets
+}
+
+func (g *Graph) GraphBuilder(edges Edges) {
+	for _, edge := range edges {
+		g.MakeEdge(edge.Start, edge.End, edge.Cost, edge.Benefit)
+		edge.Start.node.incidence = edge.Start.node.incidence + 1
+		edge.End.node.incidence = edge.End.node.incidence + 1
+	}
+}
+
+func (g *Graph) PositiveGraphBuilder(edges Edges) {
+	for _, edge := range edges {
+		if edge.Benefit-edge.Cost >= 0 {
+			g.MakeEdge(edge.Start, edge.End, edge.Cost, edge.Benefit)
+			edge.Start.node.incidence = edge.Start.node.incidence + 1
+			edge.End.node.incidence = edge.End.node.incidence + 1
+		}
+	}
+}
+
+func (g *Graph) unseeNodes() {
+	for _, node := range g.nodes {
+		node.state = unseen
+	}
+}
+
+func (g *Graph) checkIncidence() {
+	totalNodes := 0
+	for _, node := range g.nodes {
+		if node.incidence%2 != 0 {
+			totalNodes++
+		}
+	}
+}
+
+// EulerianCycle walks every edge of g once from start with Hierholzer's
+// algorithm, always going on to the lowest numbered neighbour left, so the
+// same graph gives the same tour. It returns the vertex ids of the cycle
+// last to first, false if some node has odd degree, and the sum of
+// benefit - cost over the edges walked, every parallel copy included.
+func (g *Graph) EulerianCycle(start Node) (tour []int, success bool, value int) {
+	return g.EulerianCycleRand(start, nil)
+}
+
+// EulerianCycleRand is EulerianCycle taking, at every step, a random
+// edge drawn from rng instead of one to the lowest numbered neighbour. A
+// nil rng gives the tour of EulerianCycle, the same for the same graph.
+func (g *Graph) EulerianCycleRand(start Node, rng *rand.Rand) (tour []int, success bool, value int) {
+	ids, ok := g.EulerianCycleEdges(start, rng)
+	if !ok {
+		return nil, false, 0
+	}
+	return g.walkTour(start, ids), true, g.WalkValue(ids)
+}
+
+// EulerianCycleEdges is EulerianCycleRand returning the ids of the edges of
+// the cycle in the order they are walked from start, so parallel edges,
+// such as the copies added along matching paths, each appear once.
+func (g *Graph) EulerianCycleEdges(start Node, rng *rand.Rand) ([]int, bool) {
+	for _, node := range g.nodes {
+		if g.Degree(node.container)%2 != 0 {
+			return nil, false
+		}
+	}
+	return g.eulerWalk(start, rng), true
+}
+
+// DirectedEulerianCycle is EulerianCycle for a graph built with MakeArc:
+// it walks every arc once, in its direction, and fails unless every node
+// has as many arcs in as out. Like EulerianCycle, it returns the nodes of
+// the cycle last to first.
+func (g *Graph) DirectedEulerianCycle(start Node) (tour []int, success bool, value int) {
+	return g.DirectedEulerianCycleRand(start, nil)
+}
+
+// DirectedEulerianCycleRand is DirectedEulerianCycle taking a random
+// unvisited arc drawn from rng at every step, or one to the lowest
+// numbered neighbour when rng is nil.
+func (g *Graph) DirectedEulerianCycleRand(start Node, rng *rand.Rand) (tour []int, success bool, value int) {
+	ids, ok := g.DirectedEulerianCycleEdges(start, rng)
+	if !ok {
+		return nil, false, 0
+	}
+	return g.walkTour(start, ids), true, g.WalkValue(ids)
+}
+
+// DirectedEulerianCycleEdges is DirectedEulerianCycleRand returning the ids
+// of the arcs of the cycle in the order they are walked from start.
+func (g *Graph) DirectedEulerianCycleEdges(start Node, rng *rand.Rand) ([]int, bool) {
+	for _, node := range g.nodes {
+		if g.InDegree(node.container) != g.OutDegree(node.container) {
+			return nil, false
+		}
+	}
+	return g.eulerWalk(start, rng), true
+}
+
+// eulerWalk runs Hierholzer's algorithm from start over the edges leaving
+// each node, marking edge ids as used so that an undirected edge, listed at
+// both ends, is walked once. It returns the ids walked, first to last.
+func (g *Graph) eulerWalk(start Node, rng *rand.Rand) []int {
+	unvisited := make([][]edge, len(g.nodes))
+	for _, node := range g.nodes {
+		edges := append([]edge{}, node.edges...)
+		// Taken from the end: the lowest numbered neighbour, and among
+		// parallel edges the first made, go last.
+		sort.Slice(edges, func(i, j int) bool {
+			if edges[i].end.index != edges[j].end.index {
+				return edges[i].end.index > edges[j].end.index
+			}
+			return edges[i].id > edges[j].id
+		})
+		unvisited[node.index] = edges
+	}
+	used := make([]bool, len(g.ends))
+	type step struct {
+		at *node
+		id int // edge walked to reach at, -1 for start
+	}
+	stack := []step{{start.node, -1}}
+	ids := []int{}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		if next, ok := takeEdge(&unvisited[current.at.index], used, rng); ok {
+			used[next.id] = true
+			stack = append(stack, step{next.end, next.id})
+		} else {
+			if current.id >= 0 {
+				ids = append(ids, current.id)
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	for i, j := 0, len(ids)-1; i < j; i, j = i+1, j-1 {
+		ids[i], ids[j] = ids[j], ids[i]
+	}
+	return ids
+}
+
+// takeEdge removes from edges and returns an edge whose id is not used yet:
+// the last one, or a random one when rng is not nil.
+func takeEdge(edges *[]edge, used []bool, rng *rand.Rand) (edge, bool) {
+	for len(*edges) > 0 {
+		last := len(*edges) - 1
+		i := last
+		if rng != nil {
+			i = rng.Intn(len(*edges))
+		}
+		e := (*edges)[i]
+		(*edges)[i] = (*edges)[last]
+		*edges = (*edges)[:last]
+		if !used[e.id] {
+			return e, true
+		}
+	}
+	return edge{}, false
+}
+
+// walkTour is WalkVertices last to first, like EulerianCycle.
+func (g *Graph) walkTour(start Node, ids []int) []int {
+	tour := g.WalkVertices(start, ids)
+	for i, j := 0, len(tour)-1; i < j; i, j = i+1, j-1 {
+		tour[i], tour[j] = tour[j], tour[i]
+	}
+	return tour
+}
+
+// WalkVertices returns the nodes met walking the edges ids from start,
+// start included, numbered from 1 by index like in EulerianCycle.
+func (g *Graph) WalkVertices(start Node, ids []int) []int {
+	at := start.node
+	tour := []int{at.index + 1}
+	for _, id := range ids {
+		if ends := g.ends[id]; ends[0] == at {
+			at = ends[1]
+		} else {
+			at = ends[0]
+		}
+		tour = append(tour, at.index+1)
+	}
+	return tour
+}
+
+// WalkValue is the sum of benefit - cost over the edges ids, counting
+// every time an edge appears.
+func (g *Graph) WalkValue(ids []int) int {
+	value := 0
+	for _, id := range ids {
+		for _, e := range g.ends[id][0].edges {
+			if e.id == id {
+				value += e.benefit - e.cost
+				break
+			}
+		}
+	}
+	return value
+}
+
+// OutDegree counts the arcs leaving n.
+func (g *Graph) OutDegree(n Node) int {
+	return len(n.node.edges)
+}
+
+// InDegree counts the arcs entering n, made with MakeArc.
+func (g *Graph) InDegree(n Node) int {
+	return len(n.node.reversedEdges)
+}
+
+// Degree counts the edges at n, a self-loop twice since MakeEdge stores it
+// once.
+func (g *Graph) Degree(n Node) int {
+	degree := len(n.node.edges)
+	for _, edge := range n.node.edges {
+		if edge.end == n.node {
+			degree++
+		}
+	}
+	return degree
+}
+
+func (g *Graph) FloydWarshall() (mincost, minpath [][]int) {
+	path := make([][]int, len(g.nodes))
+	next := make([][]int, len(g.nodes))
+	// Build Distance Matrix
+	lenNodes := len(g.nodes)
+	for i := 0; i < lenNodes; i++ {
+		path[i] = make([]int, lenNodes)
+		next[i] = make([]int, lenNodes)
+		for j := 0; j < lenNodes; j++ {
+			path[i][j] = math.MaxInt32
+			next[i][j] = -1
+		}
+		path[i][i] = 0
+		for _, edge := range g.nodes[i].edges {
+			path[i][edge.end.index] = edge.cost
+			next[i][edge.end.index] = edge.end.index
+		}
+	}
+	// Floyd Warshall Algorithm
+	for k := 0; k < lenNodes; k++ {
+		for i := 0; i < lenNodes; i++ {
+			for j := 0; j < lenNodes; j++ {
+				dt := path[i][k] + path[k][j]
+				if path[i][j] > dt {
+					path[i][j] = dt
+					next[i][j] = next[i][k]
+				}
+			}
+		}
+	}
+	return path, next
+}
+
+func ReconstructPath(next [][]int, u, v int) []int {
+	if next[u][v] == -1 {
+		return nil
+	}
+	path := make([]int, 0)
+	for u != v {
+		u = next[u][v]
+		path = append(path, u)
+	}
+	return path
+}
+
+// disjointSets is a union-find structure over 0..n-1 with path halving.
+type disjointSets []int
+
+func newDisjointSets(n int) disjointSets {
+	parent := make(disjointSets, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return parent
+}
+
+func (d disjointSets) find(x int) int {
+	for d[x] != x {
+		d[x] = d[d[x]]
+		x = d[x]
+	}
+	return x
+}
+
+// union merges the sets of x and y and tells whether they were apart.
+func (d disjointSets) union(x, y int) bool {
+	x, y = d.find(x), d.find(y)
+	if x == y {
+		return false
+	}
+	d[x] = y
+	return true
+}
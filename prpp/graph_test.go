@@ -0,0 +1,133 @@
+package prpp
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// testGraph builds a graph of n nodes, node i holding i+1 in its Value.
+func testGraph(n int) (*Graph, []Node) {
+	g := NewGraph()
+	nodes := make([]Node, n)
+	for i := range nodes {
+		nodes[i] = g.MakeNode()
+		*nodes[i].Value = i + 1
+	}
+	return g, nodes
+}
+
+func TestRemoveNodeParallelEdges(t *testing.T) {
+	g, nodes := testGraph(3)
+	a, _ := g.MakeEdge(nodes[0], nodes[1], 1, 0)
+	b, _ := g.MakeEdge(nodes[0], nodes[1], 2, 0)
+	c, _ := g.MakeEdge(nodes[1], nodes[1], 3, 0)
+	d, _ := g.MakeEdge(nodes[1], nodes[2], 4, 0)
+	e, _ := g.MakeEdge(nodes[0], nodes[2], 5, 0)
+	f, _ := g.MakeArc(nodes[2], nodes[1], 6, 0)
+
+	g.RemoveNode(&nodes[1])
+	for _, id := range []int{a, b, c, d, f} {
+		if _, _, ok := g.EdgeEnds(id); ok {
+			t.Errorf("edge %d of the removed node is left", id)
+		}
+	}
+	from, to, ok := g.EdgeEnds(e)
+	if !ok || from != nodes[0] || to != nodes[2] {
+		t.Errorf("edge %d lost its ends", e)
+	}
+	if len(g.nodes) != 2 || nodes[2].node.index != 1 {
+		t.Fatalf("nodes not renumbered: %d left, last at %d", len(g.nodes), nodes[2].node.index)
+	}
+	for _, n := range []Node{nodes[0], nodes[2]} {
+		if g.Degree(n) != 1 || len(n.node.reversedEdges) != 0 {
+			t.Errorf("node %v keeps edges of the removed node: %v", n, n.node)
+		}
+	}
+}
+
+func TestRemoveEdgeIDParallel(t *testing.T) {
+	g, nodes := testGraph(2)
+	a, _ := g.MakeEdge(nodes[0], nodes[1], 1, 0)
+	b, _ := g.MakeEdge(nodes[0], nodes[1], 2, 0)
+	g.RemoveEdgeID(b)
+	if g.Degree(nodes[0]) != 1 || g.Degree(nodes[1]) != 1 {
+		t.Fatalf("degrees %d and %d, want 1", g.Degree(nodes[0]), g.Degree(nodes[1]))
+	}
+	if e := cheapestEdge(g, 0, 1); e.id != a {
+		t.Errorf("edge %d left, want %d", e.id, a)
+	}
+}
+
+func TestEulerianCycleEdges(t *testing.T) {
+	// Two parallel edges between 1 and 2, a triangle 2 3 4 and a loop at 3.
+	g, nodes := testGraph(4)
+	g.MakeEdge(nodes[0], nodes[1], 1, 5)
+	g.MakeEdge(nodes[0], nodes[1], 2, 0)
+	g.MakeEdge(nodes[1], nodes[2], 1, 1)
+	g.MakeEdge(nodes[2], nodes[3], 1, 1)
+	g.MakeEdge(nodes[3], nodes[1], 1, 1)
+	g.MakeEdge(nodes[2], nodes[2], 1, 4)
+
+	for _, rng := range []*rand.Rand{nil, rand.New(rand.NewSource(1)), rand.New(rand.NewSource(2))} {
+		ids, ok := g.EulerianCycleEdges(nodes[0], rng)
+		if !ok {
+			t.Fatal("no Euler cycle")
+		}
+		sorted := append([]int{}, ids...)
+		sort.Ints(sorted)
+		if want := []int{0, 1, 2, 3, 4, 5}; !reflect.DeepEqual(sorted, want) {
+			t.Fatalf("walked edges %v, want each of %v once", ids, want)
+		}
+		tour := g.WalkVertices(nodes[0], ids)
+		if tour[0] != 1 || tour[len(tour)-1] != 1 {
+			t.Errorf("tour %v not closed at 1", tour)
+		}
+		for i, id := range ids {
+			from, to, _ := g.EdgeEnds(id)
+			u, v := *from.Value, *to.Value
+			if !(u == tour[i] && v == tour[i+1]) && !(v == tour[i] && u == tour[i+1]) {
+				t.Errorf("step %d walks edge %d from %d to %d, tour goes %d to %d", i, id, u, v, tour[i], tour[i+1])
+			}
+		}
+		if value := g.WalkValue(ids); value != 5 {
+			t.Errorf("value %d, want 5", value)
+		}
+	}
+
+	ids, _ := g.EulerianCycleEdges(nodes[0], nil)
+	if want := []int{1, 2, 3, 3, 4, 2, 1}; !reflect.DeepEqual(g.WalkVertices(nodes[0], ids), want) {
+		t.Errorf("deterministic tour %v, want %v", g.WalkVertices(nodes[0], ids), want)
+	}
+
+	g.MakeEdge(nodes[0], nodes[2], 1, 0)
+	if _, ok := g.EulerianCycleEdges(nodes[0], nil); ok {
+		t.Error("Euler cycle with odd vertices")
+	}
+}
+
+func TestDirectedEulerianCycleEdges(t *testing.T) {
+	// Two parallel arcs 1 -> 2 and back over 2 -> 1 and 2 -> 3 -> 1.
+	g, nodes := testGraph(3)
+	g.MakeArc(nodes[0], nodes[1], 1, 0)
+	g.MakeArc(nodes[0], nodes[1], 1, 0)
+	g.MakeArc(nodes[1], nodes[0], 1, 0)
+	g.MakeArc(nodes[1], nodes[2], 1, 0)
+	g.MakeArc(nodes[2], nodes[0], 1, 0)
+	ids, ok := g.DirectedEulerianCycleEdges(nodes[0], nil)
+	if !ok || len(ids) != 5 {
+		t.Fatalf("walked %v, want 5 arcs", ids)
+	}
+	tour := g.WalkVertices(nodes[0], ids)
+	for i, id := range ids {
+		from, to, _ := g.EdgeEnds(id)
+		if *from.Value != tour[i] || *to.Value != tour[i+1] {
+			t.Errorf("step %d walks arc %d against its direction", i, id)
+		}
+	}
+	g.MakeArc(nodes[0], nodes[2], 1, 0)
+	if _, ok := g.DirectedEulerianCycleEdges(nodes[0], nil); ok {
+		t.Error("Euler cycle with unbalanced vertices")
+	}
+}
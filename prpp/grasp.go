@@ -0,0 +1,129 @@
+package prpp
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const defaultIterations = 100
+
+// solveGRASP runs opts.Iterations GRASP constructions, each followed by the
+// usual pipeline and Improve, and offers every solution found to best. The
+// first construction serves every profitable link, as Solve does without
+// GRASP, so GRASP never ends with a worse solution.
+func solveGRASP(ctx context.Context, inst *Instance, opts Options, best *incumbent) error {
+	iterations := opts.Iterations
+	if iterations <= 0 {
+		iterations = defaultIterations
+	}
+	rng := rand.New(rand.NewSource(opts.Seed))
+	g, _ := instanceGraph(inst)
+	paths := NewShortestPaths(g)
+	opts.Improve = true
+
+	for best.iterations < iterations {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		start := time.Now()
+		var order []int
+		var serve []bool
+		if best.iterations == 0 {
+			order, serve = ratioOrder(inst), profitableLinks(inst)
+		} else {
+			order, serve = graspConstruct(inst, paths, opts.Alpha, rng)
+		}
+		construction := time.Since(start)
+		sol, err := solvePipeline(ctx, inst, opts, order, serve, best)
+		if err != nil {
+			return err
+		}
+		sol.Stages = append([]Stage{{"grasp-construct", construction}}, sol.Stages...)
+		best.iterations++
+	}
+	return nil
+}
+
+// graspConstruct selects the profitable links to serve. At each step a
+// link is drawn at random from the restricted candidate list, the links
+// whose benefit/cost ratio is within alpha of the best remaining ratio.
+// It is served if it touches the vertices selected so far, starting with
+// the depot, or if its profit pays for its reach times the distance to
+// them; the reach of each link is drawn between 0 and 2 once per
+// construction, so a far link is worth serving in some constructions and
+// not in others. A link that does not pay waits until another one is
+// served, which may bring the selection closer, and is left out if none
+// is. The served links come first in the returned order, in the order they
+// were drawn, followed by the rest by decreasing ratio.
+func graspConstruct(inst *Instance, paths *ShortestPaths, alpha float64, rng *rand.Rand) ([]int, []bool) {
+	serve := make([]bool, len(inst.Links))
+	ratio := make([]float64, len(inst.Links))
+	reach := make([]float64, len(inst.Links))
+	candidates := []int{}
+	for k, link := range inst.Links {
+		if link.Benefit-link.minCost() >= 0 {
+			ratio[k] = linkRatio(link)
+			reach[k] = 2 * rng.Float64()
+			candidates = append(candidates, k)
+		}
+	}
+	selected := []int{inst.depot() - 1}
+	inSelected := make([]bool, inst.Vertices)
+	inSelected[inst.depot()-1] = true
+	order := []int{}
+	postponed := []int{}
+
+	for len(candidates) > 0 {
+		bestRatio, worstRatio := math.Inf(-1), math.Inf(1)
+		for _, k := range candidates {
+			bestRatio = math.Max(bestRatio, ratio[k])
+			worstRatio = math.Min(worstRatio, ratio[k])
+		}
+		threshold := bestRatio
+		if !math.IsInf(bestRatio, 1) {
+			threshold = bestRatio - alpha*(bestRatio-worstRatio)
+		}
+		rcl := []int{}
+		for i, k := range candidates {
+			if ratio[k] >= threshold {
+				rcl = append(rcl, i)
+			}
+		}
+		i := rcl[rng.Intn(len(rcl))]
+		k := candidates[i]
+		candidates[i] = candidates[len(candidates)-1]
+		candidates = candidates[:len(candidates)-1]
+
+		link := inst.Links[k]
+		u, v := link.From-1, link.To-1
+		if !inSelected[u] && !inSelected[v] {
+			nearest := math.MaxInt32
+			for _, s := range selected {
+				nearest = min(nearest, paths.Dist(u, s), paths.Dist(v, s))
+			}
+			if nearest == math.MaxInt32 || float64(link.Benefit-link.minCost()) < reach[k]*float64(nearest) {
+				postponed = append(postponed, k)
+				continue
+			}
+		}
+		serve[k] = true
+		order = append(order, k)
+		for _, w := range []int{u, v} {
+			if !inSelected[w] {
+				inSelected[w] = true
+				selected = append(selected, w)
+			}
+		}
+		candidates = append(candidates, postponed...)
+		postponed = postponed[:0]
+	}
+
+	for _, k := range ratioOrder(inst) {
+		if !serve[k] {
+			order = append(order, k)
+		}
+	}
+	return order, serve
+}
@@ -0,0 +1,56 @@
+package prpp
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestGRASPConstructionsDiffer(t *testing.T) {
+	for _, test := range []struct{ family, name string }{
+		{"DEGREE", "D1NoRPP"},
+		{"DEGREE", "D3NoRPP"},
+		{"RANDOM", "R0NoRPP"},
+		{"CHRISTOFIDES", "P05NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		g, _ := instanceGraph(inst)
+		paths := NewShortestPaths(g)
+		sets := make(map[string]bool)
+		for _, alpha := range []float64{0, 0.5, 1} {
+			for seed := int64(1); seed <= 20; seed++ {
+				_, serve := graspConstruct(inst, paths, alpha, rand.New(rand.NewSource(seed)))
+				sets[fmt.Sprint(serve)] = true
+			}
+		}
+		if len(sets) < 2 {
+			t.Errorf("%s: every construction serves the same links", test.name)
+		}
+	}
+}
+
+func TestGRASPNotWorse(t *testing.T) {
+	for _, test := range []struct{ family, name string }{
+		{"DEGREE", "D1NoRPP"},
+		{"DEGREE", "D3NoRPP"},
+		{"RANDOM", "R0NoRPP"},
+		{"RANDOM", "R10NoRPP"},
+		{"CHRISTOFIDES", "P05NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		for _, opts := range []Options{DefaultOptions(), {Improve: true}} {
+			plain, err := Solve(inst, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts.GRASP, opts.Alpha, opts.Iterations, opts.Seed = true, 0.3, 10, 1
+			grasp, err := Solve(inst, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if grasp.Value < plain.Value {
+				t.Errorf("%s prune %v: GRASP value %d, pipeline %d", test.name, opts.Prune, grasp.Value, plain.Value)
+			}
+		}
+	}
+}
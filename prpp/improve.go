@@ -0,0 +1,57 @@
+package prpp
+
+// Improve repeatedly removes from tour the closed sub-walk whose removal
+// increases the objective the most: one that starts and ends at the same
+// vertex and whose traversal costs exceed the benefit of the edges that are
+// walked only inside it. The first and last vertex of the tour are kept, so
+// a tour closed at the depot stays closed at the depot. It returns the
+// improved tour and the value recovered.
+func Improve(inst *Instance, tour []int) ([]int, int, error) {
+	steps, err := walkLinks(inst, tour)
+	if err != nil {
+		return nil, 0, err
+	}
+	costs := make([]int, 0, len(tour))
+	for i, k := range steps {
+		costs = append(costs, inst.Links[k].stepCost(tour[i]))
+	}
+	tour = append([]int{}, tour...)
+
+	count := make([]int, len(inst.Links))
+	for _, k := range steps {
+		count[k]++
+	}
+	inside := make([]int, len(inst.Links))
+	recovered := 0
+	for {
+		// Sub-walk tour[i..j] uses steps[i..j-1].
+		bestGain, bestI, bestJ := 0, -1, -1
+		for i := range steps {
+			cost, lost := 0, 0
+			for j := i; j < len(steps); j++ {
+				k := steps[j]
+				inside[k]++
+				cost += costs[j]
+				if inside[k] == count[k] {
+					lost += inst.Links[k].Benefit
+				}
+				if tour[j+1] == tour[i] && cost-lost > bestGain {
+					bestGain, bestI, bestJ = cost-lost, i, j+1
+				}
+			}
+			for j := i; j < len(steps); j++ {
+				inside[steps[j]] = 0
+			}
+		}
+		if bestI < 0 {
+			return tour, recovered, nil
+		}
+		for _, k := range steps[bestI:bestJ] {
+			count[k]--
+		}
+		steps = append(steps[:bestI], steps[bestJ:]...)
+		costs = append(costs[:bestI], costs[bestJ:]...)
+		tour = append(tour[:bestI], tour[bestJ:]...)
+		recovered += bestGain
+	}
+}
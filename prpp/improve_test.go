@@ -0,0 +1,70 @@
+package prpp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestImprove(t *testing.T) {
+	inst := parseTestInstance(t, squareInstance)
+	for _, test := range []struct {
+		name      string
+		tour      []int
+		want      []int
+		recovered int
+	}{
+		{"unprofitable detour", []int{1, 2, 3, 4, 3, 1}, []int{1, 2, 3, 1}, 9},
+		{"edge walked again", []int{1, 2, 1, 2, 3, 1}, []int{1, 2, 3, 1}, 4},
+		{"whole tour", []int{1, 3, 4, 3, 1}, []int{1}, 11},
+		{"nothing to remove", []int{1, 2, 3, 1}, []int{1, 2, 3, 1}, 0},
+		{"profitable round trip", []int{1, 2, 1}, []int{1, 2, 1}, 0},
+		{"detour back over a served edge", []int{1, 2, 3, 1, 3, 1}, []int{1, 2, 3, 1}, 2},
+	} {
+		before, err := Evaluate(inst, test.tour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tour, recovered, err := Improve(inst, test.tour)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(tour, test.want) || recovered != test.recovered {
+			t.Errorf("%s: %v recovering %d, want %v recovering %d", test.name, tour, recovered, test.want, test.recovered)
+		}
+		after, err := Evaluate(inst, tour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after.Value != before.Value+recovered {
+			t.Errorf("%s: value went from %d to %d, recovered %d", test.name, before.Value, after.Value, recovered)
+		}
+	}
+	if _, _, err := Improve(inst, []int{1, 4, 1}); err == nil {
+		t.Error("improved a walk over a missing edge")
+	}
+}
+
+func TestImproveBundled(t *testing.T) {
+	for _, test := range []struct{ family, name string }{
+		{"CHRISTOFIDES", "P05NoRPP"},
+		{"DEGREE", "D1NoRPP"},
+		{"GRID", "G5NoRPP"},
+		{"RANDOM", "R6NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		sol, err := Solve(inst, Options{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tour, recovered, err := Improve(inst, sol.Tour)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if _, err := Verify(inst, tour, inst.depot(), sol.Value+recovered); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+		}
+		if recovered < 0 {
+			t.Errorf("%s: recovered %d", test.name, recovered)
+		}
+	}
+}
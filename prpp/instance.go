@@ -0,0 +1,318 @@
+package prpp
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Link is an edge of a PRPP instance as it appears in a NoRPP file.
+// Vertices are numbered from 1. Cost is paid going from From to To and Back
+// going from To to From; they differ for windy edges. A OneWay link is an
+// arc that can only be walked from From to To.
+type Link struct {
+	From     int
+	To       int
+	Cost     int
+	Benefit  int
+	Required bool
+	Back     int
+	OneWay   bool
+}
+
+// stepCost is the cost of walking the link from vertex from.
+func (l Link) stepCost(from int) int {
+	if from == l.From {
+		return l.Cost
+	}
+	return l.Back
+}
+
+// minCost is the cost of walking the link in its cheaper direction.
+func (l Link) minCost() int {
+	if l.OneWay || l.Cost <= l.Back {
+		return l.Cost
+	}
+	return l.Back
+}
+
+// Instance is a Prize-collecting Rural Postman Problem instance. Depot is
+// the vertex where every walk starts and ends, vertex 1 when zero.
+type Instance struct {
+	Name     string
+	Vertices int
+	Links    []Link
+	Depot    int
+}
+
+// depot is the depot of inst, 1 when Depot is not set.
+func (inst *Instance) depot() int {
+	if inst.Depot == 0 {
+		return 1
+	}
+	return inst.Depot
+}
+
+// Directed tells whether some link of inst is a windy edge or an arc, so
+// the instance needs the directed pipeline of Solve.
+func (inst *Instance) Directed() bool {
+	for _, link := range inst.Links {
+		if link.OneWay || link.Back != link.Cost {
+			return true
+		}
+	}
+	return false
+}
+
+// undirected is the relaxation of inst in which every link can be walked
+// both ways at its cheaper cost. It is inst itself when inst is not
+// Directed. No walk of inst is worth more in its relaxation.
+func (inst *Instance) undirected() *Instance {
+	if !inst.Directed() {
+		return inst
+	}
+	relaxed := &Instance{Name: inst.Name, Vertices: inst.Vertices, Links: make([]Link, len(inst.Links)), Depot: inst.Depot}
+	for k, link := range inst.Links {
+		link.Cost = link.minCost()
+		link.Back = link.Cost
+		link.OneWay = false
+		relaxed.Links[k] = link
+	}
+	return relaxed
+}
+
+// ParseError reports a malformed line of an instance file.
+type ParseError struct {
+	File   string
+	Line   int // 0 when the error concerns the whole file
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Reason)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Reason)
+}
+
+// ReadInstance reads an instance in the NoRPP text format from path.
+func ReadInstance(path string) (*Instance, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	inst, err := ParseInstance(file, path)
+	if err != nil {
+		return nil, err
+	}
+	inst.Name = filepath.Base(path)
+	return inst, nil
+}
+
+// Sections of a NoRPP file, in the order they must appear.
+const (
+	sectionVertices = iota
+	sectionRequired
+	sectionNonRequired
+)
+
+// ParseInstance parses an instance in the NoRPP text format:
+//
+//	number of vertices :  6
+//	number of required edges  4
+//	1 2 2 10
+//	...
+//	number of non required edges  6
+//	1 3 10 0
+//	...
+//
+// Each edge line holds its two end vertices, cost and benefit. Blank lines
+// are ignored. name is only used in error messages.
+//
+// An optional "depot : 3" line, anywhere after the number of vertices,
+// sets the Depot; it is vertex 1 otherwise.
+//
+// An optional fifth field on an edge line gives the cost of walking the
+// edge from the second vertex to the first, making it a windy edge, or is
+// "-" for an arc that can only be walked from the first vertex to the
+// second.
+func ParseInstance(r io.Reader, name string) (*Instance, error) {
+	inst := &Instance{Name: name}
+	section := -1
+	declared := [3]int{}
+	seen := [3]bool{}
+	counted := [3]int{}
+	fail := func(line int, format string, args ...interface{}) (*Instance, error) {
+		return nil, &ParseError{name, line, fmt.Sprintf(format, args...)}
+	}
+
+	lineScanner := bufio.NewScanner(r)
+	line := 0
+	for lineScanner.Scan() {
+		line++
+		text := lineScanner.Text()
+		contents := strings.Fields(text)
+		if len(contents) == 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(contents[0]); err != nil {
+			if depot, ok := parseDepot(text); ok {
+				if !seen[sectionVertices] || inst.Depot != 0 {
+					return fail(line, "unexpected depot line")
+				}
+				if depot < 1 || depot > inst.Vertices {
+					return fail(line, "depot %q out of range 1..%d", contents[len(contents)-1], inst.Vertices)
+				}
+				inst.Depot = depot
+				continue
+			}
+			next, err := parseHeader(text)
+			if err != nil {
+				return fail(line, "%v", err)
+			}
+			if next <= section || (next != sectionVertices && !seen[sectionVertices]) {
+				return fail(line, "unexpected header %q", sectionNames[next])
+			}
+			number, err := strconv.Atoi(contents[len(contents)-1])
+			if err != nil || number < 0 {
+				return fail(line, "invalid count %q", contents[len(contents)-1])
+			}
+			section = next
+			seen[section] = true
+			declared[section] = number
+			if section == sectionVertices {
+				inst.Vertices = number
+			}
+			continue
+		}
+
+		if section != sectionRequired && section != sectionNonRequired {
+			return fail(line, "edge listed outside an edge section")
+		}
+		if len(contents) != 4 && len(contents) != 5 {
+			return fail(line, "expected 4 fields (from, to, cost, benefit) and an optional backward cost, got %d", len(contents))
+		}
+		values := [5]int{}
+		oneWay := len(contents) == 5 && contents[4] == "-"
+		if oneWay {
+			contents = contents[:4]
+		}
+		for i, field := range contents {
+			value, err := strconv.Atoi(field)
+			if err != nil {
+				return fail(line, "invalid number %q", field)
+			}
+			values[i] = value
+		}
+		for _, vertex := range values[:2] {
+			if vertex < 1 || vertex > inst.Vertices {
+				return fail(line, "vertex %d out of range 1..%d", vertex, inst.Vertices)
+			}
+		}
+		if values[2] < 0 {
+			return fail(line, "negative cost %d", values[2])
+		}
+		if values[3] < 0 {
+			return fail(line, "negative benefit %d", values[3])
+		}
+		if len(contents) == 4 {
+			values[4] = values[2]
+		} else if values[4] < 0 {
+			return fail(line, "negative backward cost %d", values[4])
+		}
+		counted[section]++
+		inst.Links = append(inst.Links, Link{values[0], values[1], values[2], values[3], section == sectionRequired, values[4], oneWay})
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, &ParseError{name, 0, err.Error()}
+	}
+
+	if !seen[sectionVertices] {
+		return fail(0, "missing \"number of vertices\" header")
+	}
+	for _, s := range []int{sectionRequired, sectionNonRequired} {
+		if counted[s] != declared[s] {
+			return fail(0, "%q header declared %d edges, found %d", sectionNames[s], declared[s], counted[s])
+		}
+	}
+	if inst.Depot == 0 {
+		inst.Depot = 1
+	}
+	return inst, nil
+}
+
+// WriteInstance writes inst in the NoRPP text format read by ParseInstance,
+// the required edges first. The backward cost is only written for windy
+// edges and arcs, and the depot line only when it is not vertex 1.
+func WriteInstance(w io.Writer, inst *Instance) error {
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "number of vertices :   %d\n", inst.Vertices)
+	if inst.depot() != 1 {
+		fmt.Fprintf(out, "depot :   %d\n", inst.depot())
+	}
+	for _, required := range []bool{true, false} {
+		count := 0
+		for _, link := range inst.Links {
+			if link.Required == required {
+				count++
+			}
+		}
+		if required {
+			fmt.Fprintf(out, "number of required edges         %d\n", count)
+		} else {
+			fmt.Fprintf(out, "number of non required edges        %d\n", count)
+		}
+		for _, link := range inst.Links {
+			if link.Required != required {
+				continue
+			}
+			switch {
+			case link.OneWay:
+				fmt.Fprintf(out, "%d %d %d %d - \n", link.From, link.To, link.Cost, link.Benefit)
+			case link.Back != link.Cost:
+				fmt.Fprintf(out, "%d %d %d %d %d \n", link.From, link.To, link.Cost, link.Benefit, link.Back)
+			default:
+				fmt.Fprintf(out, "%d %d %d %d \n", link.From, link.To, link.Cost, link.Benefit)
+			}
+		}
+	}
+	return out.Flush()
+}
+
+var sectionNames = [3]string{"number of vertices", "number of required edges", "number of non required edges"}
+
+// parseDepot reads a "depot : 3" line. A depot that is not a number is
+// returned as 0, out of range.
+func parseDepot(text string) (int, bool) {
+	fields := strings.Fields(strings.Replace(text, ":", " ", -1))
+	if len(fields) == 0 || fields[0] != "depot" {
+		return 0, false
+	}
+	if len(fields) != 2 {
+		return 0, true
+	}
+	depot, err := strconv.Atoi(fields[1])
+	if err != nil {
+		return 0, true
+	}
+	return depot, true
+}
+
+func parseHeader(text string) (int, error) {
+	normalized := strings.Join(strings.Fields(strings.Replace(text, ":", " ", -1)), " ")
+	for section := len(sectionNames) - 1; section >= 0; section-- {
+		if strings.HasPrefix(normalized, sectionNames[section]+" ") {
+			return section, nil
+		}
+	}
+	text = strings.TrimSpace(text)
+	if len(text) > 40 {
+		text = text[:40] + "..."
+	}
+	return 0, fmt.Errorf("unknown header %q", text)
+}
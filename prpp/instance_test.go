@@ -0,0 +1,96 @@
+package prpp
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseInstanceErrors(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		text   string
+		line   int
+		reason string
+	}{
+		{"no header", "1 2 3 4\n", 1, "edge listed outside an edge section"},
+		{"unknown header", "number of vertices : 3\nnumber of edges 1\n", 2, "unknown header"},
+		{"headers out of order", "number of vertices : 3\nnumber of non required edges 0\nnumber of required edges 0\n", 3, "unexpected header"},
+		{"edges before vertices", "number of required edges 0\n", 1, "unexpected header"},
+		{"bad count", "number of vertices : x\n", 1, "invalid count"},
+		{"negative count", "number of vertices : -2\n", 1, "invalid count"},
+		{"few fields", "number of vertices : 3\nnumber of required edges 1\n1 2 3\n", 3, "expected 4 fields"},
+		{"many fields", "number of vertices : 3\nnumber of required edges 1\n1 2 3 4 5 6\n", 3, "expected 4 fields"},
+		{"bad number", "number of vertices : 3\nnumber of required edges 1\n1 2 tres 4\n", 3, "invalid number"},
+		{"vertex zero", "number of vertices : 3\nnumber of required edges 1\n0 2 3 4\n", 3, "vertex 0 out of range 1..3"},
+		{"vertex above count", "number of vertices : 3\nnumber of required edges 1\n1 4 3 4\n", 3, "vertex 4 out of range 1..3"},
+		{"negative cost", "number of vertices : 3\nnumber of required edges 1\n1 2 -3 4\n", 3, "negative cost"},
+		{"negative benefit", "number of vertices : 3\nnumber of required edges 1\n1 2 3 -4\n", 3, "negative benefit"},
+		{"negative back", "number of vertices : 3\nnumber of required edges 1\n1 2 3 4 -5\n", 3, "negative backward cost"},
+		{"depot before vertices", "depot : 1\nnumber of vertices : 3\n", 1, "unexpected depot line"},
+		{"depot twice", "number of vertices : 3\ndepot : 2\ndepot : 3\n", 3, "unexpected depot line"},
+		{"depot out of range", "number of vertices : 3\ndepot : 4\n", 2, "depot \"4\" out of range"},
+		{"missing vertices", "", 0, "missing \"number of vertices\" header"},
+		{"few required", "number of vertices : 3\nnumber of required edges 2\n1 2 3 4\nnumber of non required edges 0\n", 0, "declared 2 edges, found 1"},
+		{"many non required", "number of vertices : 3\nnumber of required edges 0\nnumber of non required edges 0\n1 2 3 4\n", 0, "declared 0 edges, found 1"},
+	} {
+		_, err := ParseInstance(strings.NewReader(test.text), "bad")
+		perr, ok := err.(*ParseError)
+		if !ok {
+			t.Errorf("%s: error %v, want a ParseError", test.name, err)
+			continue
+		}
+		if perr.File != "bad" || perr.Line != test.line || !strings.Contains(perr.Reason, test.reason) {
+			t.Errorf("%s: %v, want line %d: %s", test.name, err, test.line, test.reason)
+		}
+	}
+}
+
+func TestParseInstance(t *testing.T) {
+	inst := parseTestInstance(t, `number of vertices :   4
+depot :   3
+
+number of required edges         2
+1 2 3 10
+2 3 4 12 6
+number of non required edges        2
+3 4 2 0 -
+4 4 1 5
+`)
+	want := &Instance{Name: "test", Vertices: 4, Depot: 3, Links: []Link{
+		{From: 1, To: 2, Cost: 3, Benefit: 10, Required: true, Back: 3},
+		{From: 2, To: 3, Cost: 4, Benefit: 12, Required: true, Back: 6},
+		{From: 3, To: 4, Cost: 2, Benefit: 0, Back: 2, OneWay: true},
+		{From: 4, To: 4, Cost: 1, Benefit: 5, Back: 1},
+	}}
+	if !reflect.DeepEqual(inst, want) {
+		t.Errorf("parsed %+v, want %+v", inst, want)
+	}
+	if !inst.Directed() {
+		t.Error("instance with a windy edge and an arc not directed")
+	}
+}
+
+func TestWriteInstanceRoundTrip(t *testing.T) {
+	for _, test := range []struct{ family, name string }{
+		{"ALBAIDA", "ALBAIDAANoRPP"},
+		{"CHRISTOFIDES", "P01NoRPP"},
+		{"DEGREE", "D0NoRPP"},
+		{"GRID", "G0NoRPP"},
+		{"RANDOM", "R0NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		var out bytes.Buffer
+		if err := WriteInstance(&out, inst); err != nil {
+			t.Fatal(err)
+		}
+		again, err := ParseInstance(&out, inst.Name)
+		if err != nil {
+			t.Fatalf("%s: %v", test.name, err)
+		}
+		if !reflect.DeepEqual(again, inst) {
+			t.Errorf("%s: written and read again differs", test.name)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package prpp
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The integer programming model of an instance, from its depot and with
+// the edges numbered k = 1..m in file order:
+//
+//	maximize   sum b_k x_k - sum c_k y_k
+//	x_k <= y_k                      an edge is served only if traversed
+//	sum_{k in d(v)} y_k = 2 z_v     every vertex has even degree
+//	y_k <= 2 w_u, y_k <= 2 w_v      w_v = 1 if v is visited (v != depot)
+//	f_k_f, f_k_b <= (n-1) y_k       flow only on traversed edges
+//	in(v) - out(v) = w_v            the depot sends one unit of flow to
+//	                                every visited vertex, so the traversed
+//	                                edges are connected to it
+//	x_k, w_v binary, y_k in {0, 1, 2}, z_v integer, f >= 0
+//
+// An optimal tour never needs to traverse an edge more than twice.
+
+type term struct {
+	coef int
+	name string
+}
+
+type row struct {
+	name  string
+	terms []term
+	sense string // "<=", ">=" or "="
+	rhs   int
+}
+
+type column struct {
+	name    string
+	upper   int // -1 when unbounded
+	integer bool
+}
+
+type model struct {
+	name      string
+	depot     int
+	objective []term
+	rows      []row
+	columns   []column
+}
+
+func buildModel(inst *Instance) *model {
+	n := inst.Vertices
+	m := &model{name: inst.Name, depot: inst.depot()}
+	incident := make([][]term, n+1)
+	inflow := make([][]term, n+1)
+	for k, link := range inst.Links {
+		x := fmt.Sprintf("x_%d", k+1)
+		y := fmt.Sprintf("y_%d", k+1)
+		forward := fmt.Sprintf("f_%d_f", k+1)
+		backward := fmt.Sprintf("f_%d_b", k+1)
+		m.columns = append(m.columns, column{x, 1, true}, column{y, 2, true})
+		m.objective = append(m.objective, term{link.Benefit, x}, term{-link.Cost, y})
+		m.rows = append(m.rows, row{fmt.Sprintf("serve_%d", k+1), []term{{1, x}, {-1, y}}, "<=", 0})
+		ends := []int{link.From, link.To}
+		if link.From == link.To {
+			ends = ends[:1]
+		}
+		// A loop can only be walked at a vertex the tour visits.
+		for _, v := range ends {
+			if v != m.depot {
+				m.rows = append(m.rows, row{fmt.Sprintf("visit_%d_%d", k+1, v), []term{{1, y}, {-2, fmt.Sprintf("w_%d", v)}}, "<=", 0})
+			}
+		}
+		if link.From == link.To {
+			incident[link.From] = append(incident[link.From], term{2, y})
+			continue
+		}
+		incident[link.From] = append(incident[link.From], term{1, y})
+		incident[link.To] = append(incident[link.To], term{1, y})
+		m.columns = append(m.columns, column{forward, -1, false}, column{backward, -1, false})
+		m.rows = append(m.rows,
+			row{fmt.Sprintf("cap_%d_f", k+1), []term{{1, forward}, {-(n - 1), y}}, "<=", 0},
+			row{fmt.Sprintf("cap_%d_b", k+1), []term{{1, backward}, {-(n - 1), y}}, "<=", 0})
+		inflow[link.To] = append(inflow[link.To], term{1, forward})
+		inflow[link.From] = append(inflow[link.From], term{-1, forward})
+		inflow[link.From] = append(inflow[link.From], term{1, backward})
+		inflow[link.To] = append(inflow[link.To], term{-1, backward})
+	}
+	for v := 1; v <= n; v++ {
+		if len(incident[v]) == 0 {
+			continue
+		}
+		z := fmt.Sprintf("z_%d", v)
+		m.columns = append(m.columns, column{z, len(incident[v]), true})
+		m.rows = append(m.rows, row{fmt.Sprintf("parity_%d", v), append(incident[v], term{-2, z}), "=", 0})
+		if v != m.depot {
+			w := fmt.Sprintf("w_%d", v)
+			m.columns = append(m.columns, column{w, 1, true})
+			m.rows = append(m.rows, row{fmt.Sprintf("flow_%d", v), append(inflow[v], term{-1, w}), "=", 0})
+		}
+	}
+	return m
+}
+
+// errDirectedModel is returned for Directed instances, which the model
+// does not cover.
+var errDirectedModel = errors.New("the integer programming model only covers undirected instances")
+
+// WriteLP writes the integer programming model of inst in CPLEX LP format.
+func WriteLP(w io.Writer, inst *Instance) error {
+	if inst.Directed() {
+		return errDirectedModel
+	}
+	m := buildModel(inst)
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "\\ PRPP model of %s, depot %d\n", m.name, m.depot)
+	fmt.Fprintln(out, "Maximize")
+	writeLPTerms(out, " obj:", m.objective)
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "Subject To")
+	for _, r := range m.rows {
+		writeLPTerms(out, " "+r.name+":", r.terms)
+		fmt.Fprintf(out, " %s %d\n", r.sense, r.rhs)
+	}
+	fmt.Fprintln(out, "Bounds")
+	for _, c := range m.columns {
+		if c.upper >= 0 {
+			fmt.Fprintf(out, " 0 <= %s <= %d\n", c.name, c.upper)
+		}
+	}
+	writeLPSection(out, "General", m.columns, func(c column) bool { return c.integer && c.upper != 1 })
+	writeLPSection(out, "Binary", m.columns, func(c column) bool { return c.integer && c.upper == 1 })
+	fmt.Fprintln(out, "End")
+	return out.Flush()
+}
+
+// writeLPTerms writes a linear expression, wrapping long ones since LP
+// readers limit the line length.
+func writeLPTerms(out *bufio.Writer, label string, terms []term) {
+	out.WriteString(label)
+	for i, t := range terms {
+		if i > 0 && i%8 == 0 {
+			out.WriteString("\n  ")
+		}
+		sign := "+"
+		coef := t.coef
+		if coef < 0 {
+			sign, coef = "-", -coef
+		}
+		fmt.Fprintf(out, " %s %d %s", sign, coef, t.name)
+	}
+	if len(terms) == 0 {
+		out.WriteString(" 0")
+	}
+}
+
+func writeLPSection(out *bufio.Writer, title string, columns []column, include func(column) bool) {
+	names := []string{}
+	for _, c := range columns {
+		if include(c) {
+			names = append(names, c.name)
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+	fmt.Fprintln(out, title)
+	for i := 0; i < len(names); i += 10 {
+		end := i + 10
+		if end > len(names) {
+			end = len(names)
+		}
+		fmt.Fprintf(out, " %s\n", strings.Join(names[i:end], " "))
+	}
+}
+
+// WriteMPS writes the integer programming model of inst in free MPS format.
+// Not every MPS reader accepts an objective sense, so the model minimizes
+// the negated objective: the optimum of the instance is minus the optimal
+// value reported by the solver.
+func WriteMPS(w io.Writer, inst *Instance) error {
+	if inst.Directed() {
+		return errDirectedModel
+	}
+	m := buildModel(inst)
+	entries := make(map[string][]term)
+	for _, t := range m.objective {
+		entries[t.name] = append(entries[t.name], term{-t.coef, "obj"})
+	}
+	for _, r := range m.rows {
+		for _, t := range r.terms {
+			entries[t.name] = append(entries[t.name], term{t.coef, r.name})
+		}
+	}
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "* PRPP model of %s, depot %d; minimizes -(benefit - cost)\n", m.name, m.depot)
+	fmt.Fprintf(out, "NAME %s\n", strings.Replace(m.name, " ", "_", -1))
+	fmt.Fprintln(out, "ROWS")
+	fmt.Fprintln(out, " N obj")
+	senses := map[string]string{"<=": "L", ">=": "G", "=": "E"}
+	for _, r := range m.rows {
+		fmt.Fprintf(out, " %s %s\n", senses[r.sense], r.name)
+	}
+	fmt.Fprintln(out, "COLUMNS")
+	integer := false
+	for _, c := range m.columns {
+		if c.integer != integer {
+			marker := "INTEND"
+			if c.integer {
+				marker = "INTORG"
+			}
+			fmt.Fprintf(out, "    MARKER 'MARKER' '%s'\n", marker)
+			integer = c.integer
+		}
+		for _, e := range entries[c.name] {
+			fmt.Fprintf(out, "    %s %s %d\n", c.name, e.name, e.coef)
+		}
+	}
+	if integer {
+		fmt.Fprintln(out, "    MARKER 'MARKER' 'INTEND'")
+	}
+	fmt.Fprintln(out, "RHS")
+	for _, r := range m.rows {
+		if r.rhs != 0 {
+			fmt.Fprintf(out, "    rhs %s %d\n", r.name, r.rhs)
+		}
+	}
+	fmt.Fprintln(out, "BOUNDS")
+	for _, c := range m.columns {
+		if c.upper >= 0 {
+			fmt.Fprintf(out, " UP bnd %s %d\n", c.name, c.upper)
+		} else if c.integer {
+			fmt.Fprintf(out, " PL bnd %s\n", c.name)
+		}
+	}
+	fmt.Fprintln(out, "ENDATA")
+	return out.Flush()
+}
+
+var (
+	xmlVariable  = regexp.MustCompile(`<variable\b[^>]*\bname="([^"]+)"[^>]*\bvalue="([^"]+)"`)
+	modelColumn  = regexp.MustCompile(`^[xyzwf]_\d+(_[fb])?$`)
+	traversalVar = regexp.MustCompile(`^y_(\d+)$`)
+)
+
+// ReadModelSolution reads the values of a solution of the model written by
+// WriteLP or WriteMPS, as saved by common MIP solvers: "name value" lines
+// (Gurobi, SCIP), "index name value ..." lines (CBC) or the <variable>
+// elements of a CPLEX XML solution. It returns the closed walk from the
+// depot that traverses every edge k exactly y_k times.
+func ReadModelSolution(inst *Instance, path string) ([]int, error) {
+	if inst.Directed() {
+		return nil, errDirectedModel
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	traversals := make([]int, len(inst.Links))
+	lineScanner := bufio.NewScanner(file)
+	lineScanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for lineScanner.Scan() {
+		line++
+		text := lineScanner.Text()
+		pairs := [][2]string{}
+		if match := xmlVariable.FindAllStringSubmatch(text, -1); match != nil {
+			for _, m := range match {
+				pairs = append(pairs, [2]string{m[1], m[2]})
+			}
+		} else {
+			contents := strings.Fields(text)
+			for i := 0; i+1 < len(contents); i++ {
+				if modelColumn.MatchString(contents[i]) {
+					pairs = append(pairs, [2]string{contents[i], contents[i+1]})
+					break
+				}
+			}
+		}
+		for _, pair := range pairs {
+			match := traversalVar.FindStringSubmatch(pair[0])
+			if match == nil {
+				continue
+			}
+			k, _ := strconv.Atoi(match[1])
+			if k < 1 || k > len(inst.Links) {
+				return nil, &ParseError{path, line, fmt.Sprintf("variable %s does not match an edge of %s", pair[0], inst.Name)}
+			}
+			value, err := strconv.ParseFloat(pair[1], 64)
+			if err != nil {
+				return nil, &ParseError{path, line, fmt.Sprintf("invalid value %q for %s", pair[1], pair[0])}
+			}
+			traversals[k-1] = int(math.Floor(value + 0.5))
+		}
+	}
+	if err := lineScanner.Err(); err != nil {
+		return nil, err
+	}
+	return traversalTour(inst, traversals, inst.depot())
+}
+
+// traversalTour walks an Eulerian cycle from depot over a multigraph with
+// traversals[k] copies of every link k. It fails if some copies cannot be
+// reached from the depot or a vertex has odd degree.
+func traversalTour(inst *Instance, traversals []int, depot int) ([]int, error) {
+	g := NewGraph()
+	nodes := make(map[int]Node, inst.Vertices)
+	for i := 1; i < inst.Vertices+1; i++ {
+		nodes[i] = g.MakeNode()
+		*nodes[i].Value = i
+	}
+	total := 0
+	for k, link := range inst.Links {
+		for c := 0; c < traversals[k]; c++ {
+			g.MakeEdge(nodes[link.From], nodes[link.To], link.Cost, link.Benefit)
+			total++
+		}
+	}
+	ids, ok := g.EulerianCycleEdges(nodes[depot], nil)
+	if !ok {
+		return nil, fmt.Errorf("traversed edges leave vertices of odd degree")
+	}
+	if len(ids) != total {
+		return nil, fmt.Errorf("traversed edges are not connected to the depot %d", depot)
+	}
+	return g.WalkVertices(nodes[depot], ids), nil
+}
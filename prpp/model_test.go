@@ -0,0 +1,27 @@
+package prpp
+
+import "testing"
+
+func TestModelVisitRows(t *testing.T) {
+	// The loop at 3 pays only if the tour gets there, over 2-3.
+	inst := parseTestInstance(t, `number of vertices : 3
+number of required edges 2
+1 2 1 10
+3 3 1 5
+number of non required edges 1
+2 3 10 0
+`)
+	m := buildModel(inst)
+	rows := make(map[string]bool)
+	for _, r := range m.rows {
+		rows[r.name] = true
+	}
+	for _, name := range []string{"visit_1_2", "visit_2_3", "visit_3_2", "visit_3_3"} {
+		if !rows[name] {
+			t.Errorf("no row %s", name)
+		}
+	}
+	if rows["visit_1_1"] {
+		t.Error("visit row for the depot")
+	}
+}
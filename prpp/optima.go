@@ -0,0 +1,29 @@
+package prpp
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed optima.txt
+var builtinOptima string
+
+// KnownOptima returns the registry of optimal values shipped in optima.txt,
+// updated with the values read from overrides when it is not empty.
+func KnownOptima(overrides string) (map[string]int, error) {
+	optima, err := parseOptima(strings.NewReader(builtinOptima), "optima.txt")
+	if err != nil {
+		return nil, err
+	}
+	if overrides == "" {
+		return optima, nil
+	}
+	extra, err := ReadOptima(overrides)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range extra {
+		optima[name] = value
+	}
+	return optima, nil
+}
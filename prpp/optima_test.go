@@ -0,0 +1,50 @@
+package prpp
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestKnownOptima(t *testing.T) {
+	optima, err := KnownOptima("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := FindInstances([]string{".."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundled := make(map[string]string)
+	for _, path := range paths {
+		bundled[filepath.Base(path)] = path
+	}
+	if len(optima) == 0 {
+		t.Fatal("optima.txt lists no optimum")
+	}
+	// An optimum is the value of a walk from the depot of the instance
+	// file, so no heuristic walk from there is worth more and no bound
+	// is lower.
+	for name, optimum := range optima {
+		path, ok := bundled[name]
+		if !ok {
+			t.Errorf("%s: not a bundled instance", name)
+			continue
+		}
+		inst, err := ReadInstance(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, opts := range []Options{DefaultOptions(), {Prune: true, PruneRepeat: true, Improve: true}} {
+			sol, err := Solve(inst, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if sol.Value > optimum {
+				t.Errorf("%s: heuristic value %d above the optimum %d", name, sol.Value, optimum)
+			}
+		}
+		if bound := UpperBound(inst); optimum > bound {
+			t.Errorf("%s: optimum %d above the upper bound %d", name, optimum, bound)
+		}
+	}
+}
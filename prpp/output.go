@@ -0,0 +1,158 @@
+package prpp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteSolution writes the value of s and its tour wrapped in depot markers,
+// the format of the -salida.txt files.
+func WriteSolution(w io.Writer, s *Solution) error {
+	stringPath := make([]string, 0, len(s.Tour))
+	for _, number := range s.Tour {
+		stringPath = append(stringPath, strconv.Itoa(number))
+	}
+	out := bufio.NewWriter(w)
+	out.WriteString(strconv.Itoa(s.Value))
+	out.WriteString("\n")
+	out.WriteString("d " + strings.Join(stringPath, " ") + " d")
+	return out.Flush()
+}
+
+// SolutionJSON is the JSON form of a solution written by WriteSolutionJSON.
+type SolutionJSON struct {
+	Instance   string      `json:"instance"`
+	Depot      int         `json:"depot"`
+	Tour       []int       `json:"tour"`
+	Edges      []EdgeJSON  `json:"edges"`
+	Benefit    int         `json:"benefit"`
+	Cost       int         `json:"cost"`
+	Value      int         `json:"value"`
+	UpperBound int         `json:"upper_bound"`
+	Gap        float64     `json:"gap"`
+	Optimum    *int        `json:"optimum,omitempty"`
+	Deviation  *float64    `json:"deviation,omitempty"`
+	Stopped    bool        `json:"stopped"`
+	Stages     []StageJSON `json:"stages"`
+}
+
+// EdgeJSON is an edge walked by the tour. Cost is what its Count
+// traversals cost, each in the direction walked, and Benefit what the tour
+// collected on the first of them; Served tells whether that was anything.
+type EdgeJSON struct {
+	Index   int  `json:"index"` // position in the instance file, from 1
+	From    int  `json:"from"`
+	To      int  `json:"to"`
+	Cost    int  `json:"cost"`
+	Benefit int  `json:"benefit"`
+	Count   int  `json:"count"`
+	Served  bool `json:"served"`
+}
+
+// StageJSON is the time spent in one stage of the pipeline.
+type StageJSON struct {
+	Name string  `json:"name"`
+	Ms   float64 `json:"ms"`
+}
+
+// WriteSolutionJSON writes s, found for inst, as an indented SolutionJSON.
+// The optimum and the deviation from it are only written when known.
+func WriteSolutionJSON(w io.Writer, inst *Instance, s *Solution, optimum int, known bool) error {
+	out := SolutionJSON{
+		Instance:   inst.Name,
+		Depot:      inst.depot(),
+		Tour:       s.Tour,
+		Edges:      make([]EdgeJSON, 0, len(s.Edges)),
+		Benefit:    s.Benefit,
+		Cost:       s.Cost,
+		Value:      s.Value,
+		UpperBound: s.UpperBound,
+		Gap:        s.Gap(),
+		Stopped:    s.Stopped,
+		Stages:     make([]StageJSON, 0, len(s.Stages)),
+	}
+	for _, t := range s.Edges {
+		out.Edges = append(out.Edges, EdgeJSON{t.Index + 1, t.Link.From, t.Link.To, t.Cost, t.Benefit, t.Count, t.Benefit > 0})
+	}
+	if known {
+		out.Optimum = &optimum
+		if optimum != 0 {
+			deviation := 100 * (float64(optimum) - float64(s.Value)) / float64(optimum)
+			out.Deviation = &deviation
+		}
+	}
+	for _, stage := range s.Stages {
+		out.Stages = append(out.Stages, StageJSON{stage.Name, float64(stage.Duration) / float64(time.Millisecond)})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// WriteFleetSolution writes the combined value of fs and one line with
+// the route of each vehicle wrapped in depot markers, the -salida.txt
+// format with a walk line per vehicle.
+func WriteFleetSolution(w io.Writer, fs *FleetSolution) error {
+	out := bufio.NewWriter(w)
+	out.WriteString(strconv.Itoa(fs.Value))
+	for _, route := range fs.Routes {
+		stringPath := make([]string, 0, len(route.Tour))
+		for _, number := range route.Tour {
+			stringPath = append(stringPath, strconv.Itoa(number))
+		}
+		out.WriteString("\n")
+		out.WriteString("d " + strings.Join(stringPath, " ") + " d")
+	}
+	return out.Flush()
+}
+
+// ReadSolution reads a file written by WriteSolution and returns the declared
+// value and the walk, without the depot markers.
+func ReadSolution(path string) (value int, walk []int, err error) {
+	value, walks, err := ReadFleetSolution(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	return value, walks[0], nil
+}
+
+// ReadFleetSolution reads a file written by WriteSolution or
+// WriteFleetSolution and returns the declared value and every walk.
+func ReadFleetSolution(path string) (value int, walks [][]int, err error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) < 2 {
+		return 0, nil, fmt.Errorf("%s: expected a value line and a walk line", path)
+	}
+	value, err = strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil {
+		return 0, nil, fmt.Errorf("%s:1: invalid value %q", path, strings.TrimSpace(lines[0]))
+	}
+	for i, line := range lines[1:] {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		walk := []int{}
+		for _, field := range strings.Fields(line) {
+			if field == "d" {
+				continue
+			}
+			vertex, err := strconv.Atoi(field)
+			if err != nil {
+				return 0, nil, fmt.Errorf("%s:%d: invalid vertex %q", path, i+2, field)
+			}
+			walk = append(walk, vertex)
+		}
+		walks = append(walks, walk)
+	}
+	return value, walks, nil
+}
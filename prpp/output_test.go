@@ -0,0 +1,52 @@
+package prpp
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestWriteSolutionJSON(t *testing.T) {
+	// 1-2 costs 2 going from 1 and 5 back, 2 3 is an arc and 3-1 costs 3
+	// going from 3 and 1 back.
+	inst := parseTestInstance(t, `number of vertices : 3
+number of required edges 2
+1 2 2 10 5
+2 3 1 4 -
+number of non required edges 1
+3 1 3 0 1
+`)
+	for _, test := range []struct {
+		name  string
+		walk  []int
+		edges []EdgeJSON
+	}{
+		{"back and forth", []int{2, 1, 2}, []EdgeJSON{{1, 1, 2, 7, 10, 2, true}}},
+		{"deadhead", []int{1, 3, 1}, []EdgeJSON{{3, 3, 1, 4, 0, 2, false}}},
+		{"round", []int{1, 2, 3, 1, 3, 1}, []EdgeJSON{{1, 1, 2, 2, 10, 1, true}, {2, 2, 3, 1, 4, 1, true}, {3, 3, 1, 7, 0, 3, false}}},
+	} {
+		eval, err := Evaluate(inst, test.walk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var buf bytes.Buffer
+		if err := WriteSolutionJSON(&buf, inst, &Solution{Tour: test.walk, Evaluation: *eval}, 0, false); err != nil {
+			t.Fatal(err)
+		}
+		var out SolutionJSON
+		if err := json.Unmarshal(buf.Bytes(), &out); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(out.Edges, test.edges) {
+			t.Errorf("%s: edges %+v, want %+v", test.name, out.Edges, test.edges)
+		}
+		cost := 0
+		for _, e := range out.Edges {
+			cost += e.Cost
+		}
+		if cost != out.Cost || out.Optimum != nil {
+			t.Errorf("%s: edges cost %d of %d, optimum %v", test.name, cost, out.Cost, out.Optimum)
+		}
+	}
+}
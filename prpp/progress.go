@@ -0,0 +1,44 @@
+package prpp
+
+import "time"
+
+// Progress describes a new best solution found while solving.
+type Progress struct {
+	Value   int
+	Elapsed time.Duration // since solving started
+	Stage   string        // stage of the pipeline that found the solution
+}
+
+// incumbent keeps the best solution found so far and reports every
+// improvement to report, if it is not nil.
+type incumbent struct {
+	beginning  time.Time
+	report     func(Progress)
+	best       *Solution
+	iterations int // GRASP constructions completed
+}
+
+func newIncumbent(report func(Progress)) *incumbent {
+	return &incumbent{beginning: time.Now(), report: report}
+}
+
+// offer makes sol the incumbent if it is better than the current one.
+func (in *incumbent) offer(sol *Solution, stage string) {
+	if in.best != nil && sol.Value <= in.best.Value {
+		return
+	}
+	in.best = sol
+	if in.report != nil {
+		in.report(Progress{sol.Value, time.Since(in.beginning), stage})
+	}
+}
+
+// supersede makes sol the incumbent in place of old, a copy of an earlier
+// stage of the same tour, when old is still the incumbent and sol is as
+// good, so the solution returned holds the diagnostics of every stage.
+// Nothing is reported since the value did not change.
+func (in *incumbent) supersede(old, sol *Solution) {
+	if old != nil && in.best == old && sol.Value >= old.Value {
+		in.best = sol
+	}
+}
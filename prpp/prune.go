@@ -0,0 +1,178 @@
+package prpp
+
+import (
+	"context"
+	"time"
+)
+
+// pipelineFunc is solveOrder or solveDirected.
+type pipelineFunc func(ctx context.Context, inst *Instance, opts Options, order []int, serve []bool, best *incumbent) (*Solution, error)
+
+// solvePruned runs pipeline without the components of the served links
+// that pruneComponents finds do not pay for their connection to the depot.
+// The ones the depot cannot reach are always left out; every other branch
+// is only left out if the tour without it, improved when opts ask for it,
+// is worth more than the tour with it, since Evaluate may collect benefit
+// along the paths that link it and the matching may reuse them. Every tour
+// is offered to best and the best one is returned.
+func solvePruned(ctx context.Context, inst *Instance, opts Options, order []int, serve []bool, best *incumbent, pipeline pipelineFunc) (*Solution, error) {
+	start := time.Now()
+	g, _ := instanceGraph(inst)
+	pg, _, _ := servedGraph(inst, order, serve)
+	members, unreachable, branches := pruneComponents(g, pg, inst.depot()-1, opts.PruneRepeat)
+	serve = withoutComponents(inst, serve, members, unreachable)
+	pruned := len(unreachable)
+	elapsed := time.Since(start)
+
+	opts.Prune = false
+	sol, err := pipeline(ctx, inst, opts, order, serve, best)
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range branches {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		trial := withoutComponents(inst, serve, members, branch)
+		without, err := pipeline(ctx, inst, opts, order, trial, best)
+		if err != nil {
+			return nil, err
+		}
+		if without.Value > sol.Value {
+			sol, serve = without, trial
+			pruned += len(branch)
+		}
+	}
+	sol.Pruned = pruned
+	sol.Stages = append([]Stage{{"prune", elapsed}}, sol.Stages...)
+	return sol, nil
+}
+
+// withoutComponents copies serve leaving out the links of the components
+// of members listed in drop.
+func withoutComponents(inst *Instance, serve []bool, members [][]int, drop []int) []bool {
+	dropped := make([]bool, inst.Vertices)
+	for _, c := range drop {
+		for _, v := range members[c] {
+			dropped[v] = true
+		}
+	}
+	kept := append([]bool{}, serve...)
+	for k, link := range inst.Links {
+		if dropped[link.From-1] || dropped[link.To-1] {
+			kept[k] = false
+		}
+	}
+	return kept
+}
+
+// pruneComponents finds the components of the positive graph pg whose
+// profit does not pay for connecting them to the depot, weak components
+// when pg and g are directed. The components, and the one of depot, are
+// joined by the tree of componentTree over the shortest paths of g, the
+// tree LinkComponentsMST adds, hung from the component of depot. A
+// component's profit is the sum of benefit - cost over its edges and the
+// link to its parent in the tree costs what connectorCost charges for the
+// path added for it; the matching usually closes the way back over paths
+// it needs anyway. Without repeat every leaf of the tree whose profit does
+// not pay for its link is a branch to drop. With repeat whole branches are
+// weighed, as if the leaves were dropped again until none is left that
+// does not pay: a branch is dropped when the profit of its components,
+// less the cost of the links kept inside it, does not pay for the link to
+// its parent. It returns the node indices of each component, the
+// components g cannot reach from the depot and the branches to drop, each
+// as the components it holds.
+func pruneComponents(g, pg *Graph, depot int, repeat bool) (members [][]int, unreachable []int, branches [][]int) {
+	members, tree := pg.componentTree(g, depot)
+
+	depotComponent := -1
+	profit := make([]int, len(members))
+	counted := make([]bool, len(pg.ends))
+	for c, component := range members {
+		for _, v := range component {
+			if v == depot {
+				depotComponent = c
+			}
+			for _, edge := range pg.nodes[v].edges {
+				if !counted[edge.id] {
+					counted[edge.id] = true
+					profit[c] += edge.benefit - edge.cost
+				}
+			}
+		}
+	}
+
+	// Hang the tree from the depot; order lists the components reached,
+	// every one after its parent.
+	adjacent := make([][]componentLink, len(members))
+	for _, l := range tree {
+		adjacent[l.i] = append(adjacent[l.i], l)
+		adjacent[l.j] = append(adjacent[l.j], l)
+	}
+	parent := make([]int, len(members))
+	link := make([]int, len(members)) // net cost of the link to the parent
+	children := make([][]int, len(members))
+	reached := make([]bool, len(members))
+	reached[depotComponent] = true
+	order := []int{depotComponent}
+	for q := 0; q < len(order); q++ {
+		c := order[q]
+		for _, l := range adjacent[c] {
+			other := l.i
+			if other == c {
+				other = l.j
+			}
+			if !reached[other] {
+				reached[other] = true
+				parent[other], link[other] = c, connectorCost(g, l.path)
+				children[c] = append(children[c], other)
+				order = append(order, other)
+			}
+		}
+	}
+	for c := range members {
+		if !reached[c] {
+			unreachable = append(unreachable, c)
+		}
+	}
+
+	drop := make([]bool, len(members))
+	net := append([]int{}, profit...)
+	for q := len(order) - 1; q > 0; q-- {
+		c := order[q]
+		if !repeat && len(children[c]) > 0 {
+			continue
+		}
+		if net[c] < link[c] {
+			drop[c] = true
+		} else if repeat {
+			net[parent[c]] += net[c] - link[c]
+		}
+	}
+	// A branch starts at a dropped component whose parent is kept and
+	// goes with everything that hangs from it.
+	for _, c := range order[1:] {
+		if !drop[c] || drop[parent[c]] {
+			continue
+		}
+		branch := []int{c}
+		for i := 0; i < len(branch); i++ {
+			branch = append(branch, children[branch[i]]...)
+		}
+		branches = append(branches, branch)
+	}
+	return members, unreachable, branches
+}
+
+// connectorCost is what walking path, node indices of g, once costs net of
+// the benefit Evaluate collects on it: cost - benefit of each edge, the
+// cheapest between its nodes, or nothing for an edge that pays for itself.
+func connectorCost(g *Graph, path []int) int {
+	total := 0
+	for k := 1; k < len(path); k++ {
+		if e := cheapestEdge(g, path[k-1], path[k]); e.cost > e.benefit {
+			total += e.cost - e.benefit
+		}
+	}
+	return total
+}
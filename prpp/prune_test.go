@@ -0,0 +1,87 @@
+package prpp
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// bundledInstance reads an instance of instanciasPRPP.
+func bundledInstance(t *testing.T, family, name string) *Instance {
+	t.Helper()
+	inst, err := ReadInstance(filepath.Join("..", "instanciasPRPP", family, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inst
+}
+
+// parseTestInstance parses the instance written in text.
+func parseTestInstance(t *testing.T, text string) *Instance {
+	t.Helper()
+	inst, err := ParseInstance(strings.NewReader(text), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inst
+}
+
+func TestPruneNeverWorse(t *testing.T) {
+	// The paths that link the components of D1, D2, D4 and D20 collect
+	// benefit, so the components pay for them; the far components of G20
+	// and R12 do not.
+	for _, test := range []struct {
+		family, name string
+		pruned       bool
+	}{
+		{"DEGREE", "D1NoRPP", false},
+		{"DEGREE", "D2NoRPP", false},
+		{"DEGREE", "D4NoRPP", false},
+		{"DEGREE", "D20NoRPP", false},
+		{"RANDOM", "R2NoRPP", false},
+		{"GRID", "G20NoRPP", true},
+		{"RANDOM", "R12NoRPP", true},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		plain, err := Solve(inst, Options{Improve: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		pruned, err := Solve(inst, Options{Prune: true, PruneRepeat: true, Improve: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pruned.Value < plain.Value {
+			t.Errorf("%s: value %d pruning, %d without", test.name, pruned.Value, plain.Value)
+		}
+		if (pruned.Value > plain.Value) != test.pruned || (pruned.Pruned > 0) != test.pruned {
+			t.Errorf("%s: pruned %d components for %d over %d, want pruning %v", test.name, pruned.Pruned, pruned.Value, plain.Value, test.pruned)
+		}
+	}
+}
+
+func TestPruneRemoteBranch(t *testing.T) {
+	// 2-3 and 4-5 are close to each other but do not pay together for the
+	// path from the depot. The leaf 4-5 pays for its link to 2-3, so a
+	// single round drops nothing.
+	inst := parseTestInstance(t, `number of vertices : 5
+number of required edges 2
+2 3 1 10
+4 5 1 10
+number of non required edges 2
+1 2 100 0
+3 4 1 0
+`)
+	for _, test := range []struct {
+		repeat bool
+		pruned int
+	}{{false, 0}, {true, 2}} {
+		sol, err := Solve(inst, Options{Prune: true, PruneRepeat: test.repeat})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sol.Pruned != test.pruned {
+			t.Errorf("repeat %v: pruned %d components, want %d", test.repeat, sol.Pruned, test.pruned)
+		}
+	}
+}
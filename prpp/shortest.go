@@ -0,0 +1,134 @@
+package prpp
+
+import (
+	"container/heap"
+	"math"
+)
+
+// ShortestPaths answers shortest path queries on a graph by running
+// Dijkstra's algorithm from each source the first time it is asked for,
+// instead of computing all pairs like FloydWarshall. Nodes are identified
+// by their 0-based index in the graph. The graph must not change while the
+// ShortestPaths is in use.
+type ShortestPaths struct {
+	g     *Graph
+	trees map[int]*pathTree
+}
+
+// pathTree is the shortest path tree rooted at one source.
+type pathTree struct {
+	dist []int // math.MaxInt32 for unreachable nodes
+	prev []int // predecessor on the path from the source, -1 at the root
+}
+
+// NewShortestPaths prepares shortest path queries on g.
+func NewShortestPaths(g *Graph) *ShortestPaths {
+	return &ShortestPaths{g: g, trees: make(map[int]*pathTree)}
+}
+
+// Prepare computes the trees of the given sources ahead of the queries.
+func (sp *ShortestPaths) Prepare(sources []int) {
+	for _, source := range sources {
+		sp.tree(source)
+	}
+}
+
+// Dist returns the cost of a shortest path from u to v, or math.MaxInt32
+// when v cannot be reached, the same convention as FloydWarshall.
+func (sp *ShortestPaths) Dist(u, v int) int {
+	return sp.tree(u).dist[v]
+}
+
+// Path returns the nodes visited by a shortest path from u to v, excluding
+// u and including v, like ReconstructPath. It returns nil when v cannot be
+// reached from u.
+func (sp *ShortestPaths) Path(u, v int) []int {
+	t := sp.tree(u)
+	if t.dist[v] == math.MaxInt32 {
+		return nil
+	}
+	path := make([]int, 0)
+	for w := v; w != u; w = t.prev[w] {
+		path = append(path, w)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+func (sp *ShortestPaths) tree(source int) *pathTree {
+	if t, ok := sp.trees[source]; ok {
+		return t
+	}
+	t := sp.g.dijkstra(source)
+	sp.trees[source] = t
+	return t
+}
+
+// dijkstra computes the shortest path tree from source with a binary heap.
+func (g *Graph) dijkstra(source int) *pathTree {
+	return g.dijkstraFrom([]int{source})
+}
+
+// dijkstraFrom computes the shortest path forest rooted at all the sources,
+// giving the distance from each node to the nearest of them.
+func (g *Graph) dijkstraFrom(sources []int) *pathTree {
+	lenNodes := len(g.nodes)
+	t := &pathTree{dist: make([]int, lenNodes), prev: make([]int, lenNodes)}
+	for i := range t.dist {
+		t.dist[i] = math.MaxInt32
+		t.prev[i] = -1
+	}
+	queue := &distQueue{}
+	for _, source := range sources {
+		t.dist[source] = 0
+		*queue = append(*queue, distItem{source, 0})
+	}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(distItem)
+		if item.dist > t.dist[item.node] {
+			continue
+		}
+		for _, edge := range g.nodes[item.node].edges {
+			dt := item.dist + edge.cost
+			if dt < t.dist[edge.end.index] {
+				t.dist[edge.end.index] = dt
+				t.prev[edge.end.index] = item.node
+				heap.Push(queue, distItem{edge.end.index, dt})
+			}
+		}
+	}
+	return t
+}
+
+type distItem struct {
+	node int
+	dist int
+}
+
+// distQueue is a min-heap of tentative distances for container/heap.
+type distQueue []distItem
+
+func (q distQueue) Len() int {
+	return len(q)
+}
+
+func (q distQueue) Less(i, j int) bool {
+	return q[i].dist < q[j].dist
+}
+
+func (q distQueue) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+}
+
+func (q *distQueue) Push(x interface{}) {
+	*q = append(*q, x.(distItem))
+}
+
+func (q *distQueue) Pop() interface{} {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
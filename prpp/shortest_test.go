@@ -0,0 +1,75 @@
+package prpp
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathsMatchFloydWarshall(t *testing.T) {
+	for _, test := range []struct{ family, name string }{
+		{"ALBAIDA", "ALBAIDABNoRPP"},
+		{"CHRISTOFIDES", "P05NoRPP"},
+		{"DEGREE", "D3NoRPP"},
+		{"GRID", "G5NoRPP"},
+		{"RANDOM", "R6NoRPP"},
+	} {
+		inst := bundledInstance(t, test.family, test.name)
+		g, _ := instanceGraph(inst)
+		dist, next := g.FloydWarshall()
+		paths := NewShortestPaths(g)
+		for u := range g.nodes {
+			for v := range g.nodes {
+				if d := paths.Dist(u, v); d != dist[u][v] {
+					t.Fatalf("%s: distance from %d to %d is %d, FloydWarshall gives %d", test.name, u+1, v+1, d, dist[u][v])
+				}
+				path := paths.Path(u, v)
+				if dist[u][v] == math.MaxInt32 {
+					if path != nil || ReconstructPath(next, u, v) != nil {
+						t.Fatalf("%s: path from %d to %d that cannot be reached", test.name, u+1, v+1)
+					}
+					continue
+				}
+				cost, from := 0, u
+				for _, w := range path {
+					cost += cheapestEdge(g, from, w).cost
+					from = w
+				}
+				if from != v || cost != dist[u][v] {
+					t.Fatalf("%s: path %v from %d to %d costs %d, want %d", test.name, path, u+1, v+1, cost, dist[u][v])
+				}
+			}
+		}
+	}
+}
+
+func TestShortestPathsDirected(t *testing.T) {
+	// 1 -> 2 costs 1 but 2 -> 1 goes around over 3.
+	inst := parseTestInstance(t, `number of vertices : 3
+number of required edges 3
+1 2 1 0 -
+2 3 1 0 -
+3 1 5 0 7
+number of non required edges 0
+`)
+	g, _ := instanceGraph(inst)
+	paths := NewShortestPaths(g)
+	for _, test := range []struct {
+		u, v, dist int
+		path       []int
+	}{
+		{0, 1, 1, []int{1}},
+		{1, 0, 6, []int{2, 0}},
+		{0, 2, 2, []int{1, 2}},
+		{2, 0, 5, []int{0}},
+		{2, 1, 6, []int{0, 1}},
+		{1, 1, 0, []int{}},
+	} {
+		if d := paths.Dist(test.u, test.v); d != test.dist {
+			t.Errorf("distance from %d to %d is %d, want %d", test.u+1, test.v+1, d, test.dist)
+		}
+		if path := paths.Path(test.u, test.v); !reflect.DeepEqual(path, test.path) {
+			t.Errorf("path from %d to %d is %v, want %v", test.u+1, test.v+1, path, test.path)
+		}
+	}
+}
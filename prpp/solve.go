@@ -0,0 +1,464 @@
+// Package prpp solves the Prize-collecting Rural Postman Problem with a
+// constructive heuristic: serve every profitable edge, link the
+// resulting components, make the graph even with a minimum matching of the
+// odd vertices and walk an Eulerian cycle from the depot.
+package prpp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/dannodan/prpp/blossom"
+)
+
+// Options tunes Solve. The zero value runs the original heuristic.
+type Options struct {
+	// Prune drops components of profitable edges that do not pay for
+	// their connection before linking, as long as the tour gets better
+	// without them; with PruneRepeat whole branches of components are
+	// weighed, not only the last ones.
+	Prune       bool
+	PruneRepeat bool
+	// Improve removes unprofitable closed sub-walks from the Euler tour.
+	Improve bool
+	// EulerSeed, when not zero, makes the Euler tour go on to a random
+	// neighbour drawn with that seed at every step; otherwise it goes to
+	// the lowest numbered one and the same instance always gives the
+	// same tour.
+	EulerSeed int64
+
+	// GRASP builds many solutions from randomized greedy selections of
+	// the profitable edges and keeps the best. Alpha sets the width of the
+	// restricted candidate list, from 0 (pure greedy on benefit/cost) to 1
+	// (uniformly random). GRASP stops after Iterations constructions (100
+	// when zero). Runs with the same Seed build the same sequence of
+	// selections.
+	GRASP      bool
+	Alpha      float64
+	Iterations int
+	Seed       int64
+
+	// TimeLimit bounds the time spent solving when positive. Once it runs
+	// out, or the context of SolveContext is done, the best solution found
+	// so far is returned with Stopped set.
+	TimeLimit time.Duration
+	// Progress, if not nil, is called with every new best solution.
+	Progress func(Progress)
+}
+
+// eulerRand is the source of the random choices of the Euler tour, nil
+// when opts.EulerSeed is zero.
+func (opts Options) eulerRand() *rand.Rand {
+	if opts.EulerSeed == 0 {
+		return nil
+	}
+	return rand.New(rand.NewSource(opts.EulerSeed))
+}
+
+// DefaultOptions returns the options used by the command line tools.
+func DefaultOptions() Options {
+	return Options{Improve: true}
+}
+
+// Stage records the time spent in one step of the pipeline.
+type Stage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Diagnostics describes what each stage of Solve did.
+type Diagnostics struct {
+	Stages        []Stage
+	PositiveEdges int  // edges with benefit >= cost
+	Components    int  // connected components of the positive graph
+	Pruned        int  // components dropped by pruning
+	OddNodes      int  // odd degree, or unbalanced when directed, vertices after linking components
+	MatchedPairs  int  // matching, or balancing when directed, paths added to the positive graph
+	Recovered     int  // value gained by Improve
+	Iterations    int  // GRASP constructions run
+	Nodes         int  // branch and bound nodes explored by SolveExact
+	Stopped       bool // the time limit or the context ended the search
+
+	// Connectors are the edges added to link the components and
+	// MatchingPaths the vertices of each shortest path added for the
+	// matching, as drawn by WriteDOT.
+	Connectors    [][2]int
+	MatchingPaths [][]int
+}
+
+// Solution is the closed walk found by Solve. Its embedded Evaluation holds
+// the true objective of Tour. HeuristicValue is the value accumulated by
+// EulerianCycle, which adds benefit - cost for the deadheading copies made
+// along matching paths as if they were served again. UpperBound is the
+// bound on the optimum given by UpperBound.
+type Solution struct {
+	// Tour lists the vertex ids of the walk, starting at the depot.
+	Tour           []int
+	HeuristicValue int
+	UpperBound     int
+	Evaluation
+	Diagnostics
+}
+
+func (d *Diagnostics) track(name string, start time.Time) {
+	d.Stages = append(d.Stages, Stage{name, time.Since(start)})
+}
+
+// Gap is the percentage by which Value falls short of UpperBound, so the
+// optimum is at most that far above the solution.
+func (s *Solution) Gap() float64 {
+	if s.UpperBound <= 0 {
+		return 0
+	}
+	return float64(100 * (float64(s.UpperBound) - float64(s.Value)) / float64(s.UpperBound))
+}
+
+// Solve runs the heuristic on inst and returns the walk found from its depot.
+// With opts.GRASP it returns the best walk of the GRASP iterations instead.
+func Solve(inst *Instance, opts Options) (*Solution, error) {
+	return SolveContext(context.Background(), inst, opts)
+}
+
+// SolveContext is Solve stopping early once ctx is done, in which case it
+// returns the best solution found so far, or the walk that stays at the
+// depot if the first tour was not finished. The context is checked between
+// the stages of the pipeline and the GRASP iterations, so a stage already
+// running is finished first.
+func SolveContext(ctx context.Context, inst *Instance, opts Options) (*Solution, error) {
+	if inst.Vertices < 1 {
+		return nil, errors.New("instance has no vertices")
+	}
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+	}
+	best := newIncumbent(opts.Progress)
+	var err error
+	if opts.GRASP {
+		err = solveGRASP(ctx, inst, opts, best)
+	} else {
+		_, err = solvePipeline(ctx, inst, opts, ratioOrder(inst), profitableLinks(inst), best)
+	}
+	if err != nil && err != ctx.Err() {
+		return nil, err
+	}
+	sol := best.best
+	if err != nil {
+		if sol == nil {
+			sol = &Solution{Tour: []int{inst.depot()}}
+		}
+		sol.Stopped = true
+	}
+	if opts.GRASP {
+		sol.Iterations = best.iterations
+	}
+
+	start := time.Now()
+	sol.UpperBound = UpperBound(inst)
+	sol.track("bound", start)
+	return sol, nil
+}
+
+// SolveEachDepot runs SolveContext with every vertex of inst as the depot
+// and returns the best solution, the first depot found when several tie,
+// with the value found from each depot, values[v-1] for vertex v. The
+// time limit of opts bounds the whole search; once it runs out, or ctx is
+// done, the best solution of the depots tried so far is returned with
+// Stopped set and values only covers the depots finished. opts.Progress is not
+// called.
+func SolveEachDepot(ctx context.Context, inst *Instance, opts Options) (*Solution, []int, error) {
+	if opts.TimeLimit > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.TimeLimit)
+		defer cancel()
+		opts.TimeLimit = 0
+	}
+	opts.Progress = nil
+	var best *Solution
+	values := make([]int, 0, inst.Vertices)
+	for v := 1; v <= inst.Vertices; v++ {
+		from := *inst
+		from.Depot = v
+		sol, err := SolveContext(ctx, &from, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !sol.Stopped {
+			values = append(values, sol.Value)
+		}
+		if best == nil || sol.Value > best.Value {
+			best = sol
+		}
+		if sol.Stopped {
+			best.Stopped = true
+			break
+		}
+	}
+	return best, values, nil
+}
+
+// ratioOrder returns the indices of inst.Links by decreasing benefit/cost.
+func ratioOrder(inst *Instance) []int {
+	order := make([]int, len(inst.Links))
+	ratios := make([]float64, len(inst.Links))
+	for k, link := range inst.Links {
+		order[k] = k
+		ratios[k] = linkRatio(link)
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return ratios[order[i]] > ratios[order[j]]
+	})
+	return order
+}
+
+// profitableLinks marks the links of inst whose benefit pays for their
+// cheaper cost, the ones Solve serves.
+func profitableLinks(inst *Instance) []bool {
+	serve := make([]bool, len(inst.Links))
+	for k, link := range inst.Links {
+		serve[k] = link.Benefit-link.minCost() >= 0
+	}
+	return serve
+}
+
+// linkRatio is the benefit/cost ratio of link, at its cheaper cost, +Inf
+// for a free edge with benefit and 0 for one without, which would
+// otherwise give NaN.
+func linkRatio(link Link) float64 {
+	if link.Benefit == 0 {
+		return 0
+	}
+	return float64(link.Benefit) / float64(link.minCost())
+}
+
+// instanceGraph builds the graph of all the edges of inst. Node i holds
+// vertex i in its Value. The graph of a Directed instance is built with
+// arcs, two for each edge that can be walked both ways.
+func instanceGraph(inst *Instance) (*Graph, map[int]Node) {
+	g := NewGraph()
+	nodes := make(map[int]Node, inst.Vertices)
+	for i := 1; i < inst.Vertices+1; i++ {
+		nodes[i] = g.MakeNode()
+		*nodes[i].Value = i
+	}
+	if inst.Directed() {
+		for _, link := range inst.Links {
+			if link.OneWay {
+				g.MakeArc(nodes[link.From], nodes[link.To], link.Cost, link.Benefit)
+			} else {
+				g.MakeWindyEdge(nodes[link.From], nodes[link.To], link.Cost, link.Back, link.Benefit)
+			}
+		}
+		return g, nodes
+	}
+	edges := Edges{}
+	for _, link := range inst.Links {
+		edges = append(edges, Edge{link.Cost, link.Benefit, nodes[link.From], nodes[link.To]})
+	}
+	g.GraphBuilder(edges)
+	return g, nodes
+}
+
+// servedGraph builds the positive graph of the links marked in serve, added
+// in order, with node i holding vertex i, and counts the profitable ones.
+// Links of a Directed instance are added as arcs in their cheaper
+// direction.
+func servedGraph(inst *Instance, order []int, serve []bool) (*Graph, map[int]Node, int) {
+	positiveG := NewGraph()
+	pNodes := make(map[int]Node, 0)
+	for i := 1; i < inst.Vertices+1; i++ {
+		pNodes[i] = positiveG.MakeNode()
+		*pNodes[i].Value = i
+	}
+	positive := 0
+	if inst.Directed() {
+		for _, k := range order {
+			if link := inst.Links[k]; serve[k] {
+				from, to, cost := link.From, link.To, link.Cost
+				if !link.OneWay && link.Back < link.Cost {
+					from, to, cost = link.To, link.From, link.Back
+				}
+				positiveG.MakeArc(pNodes[from], pNodes[to], cost, link.Benefit)
+				if link.Benefit-link.minCost() >= 0 {
+					positive++
+				}
+			}
+		}
+		return positiveG, pNodes, positive
+	}
+
+	servedEdges := Edges{}
+	for _, k := range order {
+		if link := inst.Links[k]; serve[k] {
+			servedEdges = append(servedEdges, Edge{link.Cost, link.Benefit, pNodes[link.From], pNodes[link.To]})
+		}
+	}
+	positiveG.PositiveGraphBuilder(servedEdges)
+	for _, edge := range servedEdges {
+		if edge.Benefit-edge.Cost >= 0 {
+			positive++
+		}
+	}
+	return positiveG, pNodes, positive
+}
+
+// solvePipeline runs the pipeline of inst, solveDirected for a Directed
+// instance and solveOrder otherwise, through solvePruned when opts.Prune.
+func solvePipeline(ctx context.Context, inst *Instance, opts Options, order []int, serve []bool, best *incumbent) (*Solution, error) {
+	pipeline := solveOrder
+	if inst.Directed() {
+		pipeline = solveDirected
+	}
+	if opts.Prune {
+		return solvePruned(ctx, inst, opts, order, serve, best, pipeline)
+	}
+	return pipeline(ctx, inst, opts, order, serve, best)
+}
+
+// solveOrder runs the pipeline serving the links marked in serve. order is
+// a permutation of the link indices that sets the order in which the
+// served links are added to the positive graph; the components are linked
+// by LinkComponentsMST. The tours found are offered to best.
+func solveOrder(ctx context.Context, inst *Instance, opts Options, order []int, serve []bool, best *incumbent) (*Solution, error) {
+	sol := &Solution{}
+
+	start := time.Now()
+	g, _ := instanceGraph(inst)
+	positiveG, pNodes, positive := servedGraph(inst, order, serve)
+	sol.PositiveEdges = positive
+	sol.Components = len(positiveG.ConnectedComponents())
+	positiveG.unseeNodes()
+	sol.track("build", start)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// Join the components, and the depot, along a minimum spanning tree
+	// of their shortest paths
+	start = time.Now()
+	for _, path := range positiveG.LinkComponentsMST(g, inst.depot()-1) {
+		for i := 1; i < len(path); i++ {
+			sol.Connectors = append(sol.Connectors, [2]int{path[i-1] + 1, path[i] + 1})
+		}
+	}
+	sol.track("link-components", start)
+
+	// Get oddNodes
+	start = time.Now()
+	oddNodes := make([]int, 0) // List of OddNodes
+	for index := 1; index < inst.Vertices+1; index++ {
+		if positiveG.Degree(pNodes[index])%2 != 0 {
+			oddNodes = append(oddNodes, index)
+		}
+	}
+	sol.OddNodes = len(oddNodes)
+	sol.track("odd-nodes", start)
+
+	// Shortest paths are only needed between odd nodes, so run Dijkstra
+	// from each of them rather than Floyd Warshall on the whole graph
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	sources := make([]int, 0, len(oddNodes))
+	for _, index := range oddNodes {
+		sources = append(sources, index-1)
+	}
+	paths := NewShortestPaths(g)
+	paths.Prepare(sources)
+	sol.track("shortest-paths", start)
+
+	// Minimum weight perfect matching of the odd nodes over shortest paths
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	start = time.Now()
+	size := len(oddNodes)
+	m := blossom.NewMatrix(size)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			m.A[i*size+j] = int64(paths.Dist(oddNodes[i]-1, oddNodes[j]-1))
+		}
+	}
+	minMatching, err := blossom.ComputeMinPerfect(m)
+	if err != nil {
+		return nil, err
+	}
+	sol.MatchedPairs = len(minMatching)
+	sol.track("matching", start)
+
+	// Insert the shortest path between every matched pair
+	start = time.Now()
+	for _, elem := range minMatching {
+		startIndex := oddNodes[elem.Start()]
+		path := paths.Path(oddNodes[elem.Start()]-1, oddNodes[elem.End()]-1)
+		if path == nil {
+			return nil, fmt.Errorf("no path between odd vertices %d and %d", oddNodes[elem.Start()], oddNodes[elem.End()])
+		}
+		matchingPath := []int{startIndex}
+		for _, vertice := range path {
+			nextIndex := vertice + 1
+			// The shortest path takes the cheapest of parallel edges
+			edge := cheapestEdge(g, startIndex-1, vertice)
+			positiveG.MakeEdge(pNodes[startIndex], pNodes[nextIndex], edge.cost, edge.benefit)
+			startIndex = nextIndex
+			matchingPath = append(matchingPath, nextIndex)
+		}
+		sol.MatchingPaths = append(sol.MatchingPaths, matchingPath)
+	}
+
+	walk, ok := positiveG.EulerianCycleEdges(pNodes[inst.depot()], opts.eulerRand())
+	if !ok {
+		return nil, errors.New("positive graph has odd degree vertices after matching")
+	}
+	sol.Tour = positiveG.WalkVertices(pNodes[inst.depot()], walk)
+	sol.HeuristicValue = positiveG.WalkValue(walk)
+	sol.track("euler", start)
+
+	return finishTour(ctx, inst, opts, sol, best)
+}
+
+// finishTour improves the Euler tour of sol if opts ask for it, evaluates
+// it and offers it to best.
+func finishTour(ctx context.Context, inst *Instance, opts Options, sol *Solution, best *incumbent) (*Solution, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	var err error
+	var tour *Solution
+	if opts.Improve {
+		// Offer the Euler tour before improving it.
+		if eval, err := Evaluate(inst, sol.Tour); err == nil {
+			tour = &Solution{}
+			*tour = *sol
+			tour.Evaluation = *eval
+			best.offer(tour, "euler")
+		}
+		start := time.Now()
+		sol.Tour, sol.Recovered, err = Improve(inst, sol.Tour)
+		if err != nil {
+			return nil, fmt.Errorf("euler tour is not a walk of the instance: %v", err)
+		}
+		sol.track("improve", start)
+	}
+
+	start := time.Now()
+	eval, err := Evaluate(inst, sol.Tour)
+	if err != nil {
+		return nil, fmt.Errorf("euler tour is not a walk of the instance: %v", err)
+	}
+	sol.Evaluation = *eval
+	sol.track("evaluate", start)
+	if opts.Improve {
+		best.offer(sol, "improve")
+		best.supersede(tour, sol)
+	} else {
+		best.offer(sol, "euler")
+	}
+	return sol, nil
+}
@@ -0,0 +1,32 @@
+package prpp
+
+import "testing"
+
+func TestSolveStages(t *testing.T) {
+	// Improve recovers nothing on P04, P08, P11 and P13, so the Euler tour
+	// is as good as the improved one; the solution returned must still be
+	// the one that went through every stage.
+	for _, test := range []struct{ family, name string }{
+		{"CHRISTOFIDES", "P04NoRPP"},
+		{"CHRISTOFIDES", "P08NoRPP"},
+		{"CHRISTOFIDES", "P11NoRPP"},
+		{"CHRISTOFIDES", "P13NoRPP"},
+	} {
+		sol, err := Solve(bundledInstance(t, test.family, test.name), DefaultOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sol.Recovered != 0 {
+			t.Errorf("%s: recovered %d, want 0", test.name, sol.Recovered)
+		}
+		stages := make(map[string]bool)
+		for _, s := range sol.Stages {
+			stages[s.Name] = true
+		}
+		for _, name := range []string{"build", "euler", "improve", "evaluate", "bound"} {
+			if !stages[name] {
+				t.Errorf("%s: no %s stage in %v", test.name, name, sol.Stages)
+			}
+		}
+	}
+}
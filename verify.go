@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dannodan/prpp/prpp"
+)
+
+// verify checks a -salida.txt file, of one walk or of the routes of a
+// fleet, against its instance and returns the process exit status.
+func verify(args []string) int {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	setDepot := depotFlag(flags)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, "Para verificar ./main verify [opciones] <nombre-archivo> <archivo-solucion>")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 2 {
+		flags.Usage()
+		return 2
+	}
+	args = flags.Args()
+	inst, err := prpp.ReadInstance(args[0])
+	if err == nil {
+		err = setDepot(inst)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	declared, walks, err := prpp.ReadFleetSolution(args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// A file with several walks holds the routes of a fleet.
+	var eval *prpp.Evaluation
+	if len(walks) > 1 {
+		eval, err = prpp.VerifyFleet(inst, walks, inst.Depot, declared)
+	} else {
+		eval, err = prpp.Verify(inst, walks[0], inst.Depot, declared)
+	}
+	fmt.Println(args[1])
+	if eval != nil {
+		fmt.Println("Beneficio: ", eval.Benefit)
+		fmt.Println("Costo: ", eval.Cost)
+		fmt.Println("Valor Recalculado: ", eval.Value)
+	}
+	fmt.Println("Valor Declarado: ", declared)
+	if err != nil {
+		fmt.Println("Solucion invalida: ", err)
+		return 1
+	}
+	fmt.Println("Solucion valida")
+	return 0
+}